@@ -1,24 +1,105 @@
 package main
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
 
 	"github.com/gorilla/mux"
+	"github.com/scottrigby/cfp-api/pkg/certs"
 	"github.com/scottrigby/cfp-api/pkg/handlers"
+	"github.com/scottrigby/cfp-api/pkg/middleware"
+	"github.com/scottrigby/cfp-api/pkg/tracing"
 )
 
 const defaultPort = 50001
 
 func main() {
+	var tlsCertFile, tlsKeyFile, clientCAFile string
+	var tracingOptions tracing.Options
+	flag.StringVar(&tlsCertFile, "tls-cert-file", "", "The certificate file to serve HTTPS with, reloaded when it changes.")
+	flag.StringVar(&tlsKeyFile, "tls-key-file", "", "The key file of the certificate, reloaded when it changes.")
+	flag.StringVar(&clientCAFile, "tls-client-ca-file", "",
+		"The CA bundle verifying the certificates of the clients, which are required when set.")
+	flag.StringVar(&tracingOptions.Exporter, "tracing-exporter", tracing.ExporterNone,
+		"The exporter of the spans of the requests: none, stdout or otlp.")
+	flag.StringVar(&tracingOptions.Endpoint, "otlp-endpoint", tracing.DefaultOTLPEndpoint,
+		"The address of the OTLP/HTTP collector the spans are sent to with the otlp exporter.")
+	flag.Parse()
+
+	// Client certificates can only be required when serving HTTPS
+	if clientCAFile != "" && (tlsCertFile == "" || tlsKeyFile == "") {
+		log.Fatal("-tls-client-ca-file requires -tls-cert-file and -tls-key-file")
+	}
+
+	if _, err := tracing.Setup("cfp-api", tracingOptions); err != nil {
+		log.Fatal(err)
+	}
+
 	r := mux.NewRouter()
 
 	RegisterSpeakerRoutes(r)
 	RegisterProposaltRoutes(r)
+	RegisterReviewRoutes(r)
+
+	// Trace the requests, including the ones failing authentication
+	r.Use(middleware.Tracing)
+
+	// Authenticate the requests when credentials are set
+	creds := middleware.Credentials{
+		Token:    os.Getenv("CFP_API_TOKEN"),
+		Username: os.Getenv("CFP_API_USERNAME"),
+		Password: os.Getenv("CFP_API_PASSWORD"),
+	}
+	if creds.Enabled() {
+		r.Use(middleware.Auth(creds))
+		log.Println("authentication enabled")
+	}
+
+	server := &http.Server{Addr: fmt.Sprintf(":%d", defaultPort), Handler: r}
+	if tlsCertFile == "" {
+		log.Printf("listening on port %v\n", defaultPort)
+		log.Fatal(server.ListenAndServe())
+	}
 
-	log.Printf("listening on port %v\n", defaultPort)
-	log.Fatal(http.ListenAndServe(fmt.Sprintf(":%d", defaultPort), r))
+	tlsConfig, err := newTLSConfig(tlsCertFile, tlsKeyFile, clientCAFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	server.TLSConfig = tlsConfig
+	log.Printf("listening with TLS on port %v\n", defaultPort)
+	log.Fatal(server.ListenAndServeTLS("", ""))
+}
+
+// newTLSConfig returns the TLS configuration serving the certificate, and
+// requiring client certificates signed by the client CA if any.
+func newTLSConfig(certFile, keyFile, clientCAFile string) (*tls.Config, error) {
+	reloader, err := certs.NewReloader(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	config := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: reloader.GetCertificate,
+	}
+
+	if clientCAFile != "" {
+		pem, err := os.ReadFile(clientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in the client CA file %s", clientCAFile)
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
 }
 
 func RegisterSpeakerRoutes(router *mux.Router) {
@@ -36,3 +117,11 @@ func RegisterProposaltRoutes(router *mux.Router) {
 	router.HandleFunc("/api/proposals/{id}", handlers.UpdateProposal).Methods("PUT")
 	router.HandleFunc("/api/proposals/{id}", handlers.DeleteProposal).Methods("DELETE")
 }
+
+func RegisterReviewRoutes(router *mux.Router) {
+	router.HandleFunc("/api/proposals/{id}/reviews", handlers.GetReviews).Methods("GET")
+	router.HandleFunc("/api/proposals/{id}/reviews/{reviewID}", handlers.GetReviewById).Methods("GET")
+	router.HandleFunc("/api/proposals/{id}/reviews", handlers.CreateReview).Methods("POST")
+	router.HandleFunc("/api/proposals/{id}/reviews/{reviewID}", handlers.UpdateReview).Methods("PUT")
+	router.HandleFunc("/api/proposals/{id}/reviews/{reviewID}", handlers.DeleteReview).Methods("DELETE")
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/scottrigby/cfp-api/pkg/utils"
+)
+
+// listOptions are the pagination parameters of a list request: the maximum
+// number of records of the page, and the cursor returned with the previous
+// page.
+type listOptions struct {
+	limit int
+	after string
+}
+
+func parseListOptions(r *http.Request) (listOptions, error) {
+	var opts listOptions
+	errs := &utils.ValidationError{}
+	query := r.URL.Query()
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			errs.Add("limit", "invalid limit %q, must be a positive integer", v)
+		}
+		opts.limit = limit
+	}
+
+	if v := query.Get("continue"); v != "" {
+		after, err := base64.RawURLEncoding.DecodeString(v)
+		if err != nil {
+			errs.Add("continue", "invalid continue %q", v)
+		}
+		opts.after = string(after)
+	}
+	return opts, errs.OrNil()
+}
+
+// paginate sorts the items by ID, and returns the page of items following the
+// cursor of opts with the cursor of the next page, if any.
+func paginate[T any](items []T, id func(T) string, opts listOptions) ([]T, string) {
+	sort.Slice(items, func(i, j int) bool { return id(items[i]) < id(items[j]) })
+
+	start := sort.Search(len(items), func(i int) bool { return id(items[i]) > opts.after })
+	items = items[start:]
+	if opts.limit == 0 || len(items) <= opts.limit {
+		return items, ""
+	}
+
+	items = items[:opts.limit]
+	return items, base64.RawURLEncoding.EncodeToString([]byte(id(items[len(items)-1])))
+}
@@ -20,25 +20,33 @@ func CreateProposal(w http.ResponseWriter, r *http.Request) {
 	json.NewDecoder(r.Body).Decode(&proposal)
 
 	if err := validateProposal(&proposal); err != nil {
-		utils.Error(w, err.Error(), http.StatusBadRequest)
+		utils.Invalid(w, err)
 		return
 	}
 
+	recordsMu.Lock()
+	defer recordsMu.Unlock()
+
 	if utils.Exists(proposal.ID, proposalsDataPath) {
-		utils.Error(w, fmt.Sprintf("proposal with ID '%s' already exists", proposal.ID), http.StatusBadRequest)
+		utils.Error(w, fmt.Sprintf("proposal with ID '%s' already exists", proposal.ID), http.StatusConflict)
 		return
 	}
 
 	writeProposal(w, r, &proposal)
 }
 
-// GetProposal returns the data for a Proposal given the Proposal's ID.
+// GetProposal returns the data for a Proposal given the Proposal's ID, with
+// its entity tag in the ETag header.
 func GetProposalById(w http.ResponseWriter, r *http.Request) {
 	id := mux.Vars(r)["id"]
 	if id == "" {
 		utils.Error(w, "proposal ID must be specified", http.StatusBadRequest)
 		return
 	}
+
+	recordsMu.RLock()
+	defer recordsMu.RUnlock()
+
 	b, err := os.ReadFile(fmt.Sprintf("%s%s.json", proposalsDataPath, utils.MakeFileName(id)))
 
 	switch {
@@ -56,20 +64,29 @@ func GetProposalById(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	w.Header().Set("ETag", utils.ETag(b))
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(proposal)
 }
 
-// GetProposals returns a list with the data of all the files of Proposals
-// contained in "data/proposals/".
+// GetProposals returns a page of the Proposals contained in "data/proposals/",
+// filtered by the speakerID, status and type query parameters. The page holds
+// up to limit Proposals, following the continue cursor.
 func GetProposals(w http.ResponseWriter, r *http.Request) {
+	opts, err := parseListOptions(r)
+	if err != nil {
+		utils.Invalid(w, err)
+		return
+	}
+	query := r.URL.Query()
+
 	files, err := os.ReadDir(proposalsDataPath)
 	if err != nil {
 		utils.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	var proposalList []types.Proposal
+	proposalList := []types.Proposal{}
 	for _, file := range files {
 		b, err := os.ReadFile(fmt.Sprintf("%s%s", proposalsDataPath, file.Name()))
 
@@ -86,15 +103,41 @@ func GetProposals(w http.ResponseWriter, r *http.Request) {
 			utils.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
-		proposalList = append(proposalList, proposal)
+		if matchesProposal(&proposal, query.Get("speakerID"), query.Get("status"), query.Get("type")) {
+			proposalList = append(proposalList, proposal)
+		}
 	}
 
+	page := types.ProposalList{}
+	page.Items, page.Continue = paginate(proposalList, func(p types.Proposal) string { return p.ID }, opts)
+
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(proposalList)
+	json.NewEncoder(w).Encode(page)
+}
+
+// matchesProposal returns true if the Proposal is presented by the speaker,
+// and has the submission status and type, each filter being ignored if empty.
+func matchesProposal(p *types.Proposal, speakerID, status, proposalType string) bool {
+	if status != "" && p.Submission.Status != status {
+		return false
+	}
+	if proposalType != "" && p.Type != proposalType {
+		return false
+	}
+	if speakerID == "" || p.SpeakerID == speakerID {
+		return true
+	}
+	for _, id := range p.SpeakerIDs {
+		if id == speakerID {
+			return true
+		}
+	}
+	return false
 }
 
 // UpdateProposal checks that a file for a Proposal exists given its ID
-// then replaces the data for that Proposal by overwriting it.
+// then replaces the data for that Proposal by overwriting it, unless it does
+// not match the If-Match header of the request.
 func UpdateProposal(w http.ResponseWriter, r *http.Request) {
 	var proposal types.Proposal
 	json.NewDecoder(r.Body).Decode(&proposal)
@@ -106,28 +149,34 @@ func UpdateProposal(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if utils.MakeFileName(proposal.ID) != id {
-		utils.Error(w, fmt.Sprintf("proposal ID '%s' used as query param does not match ID in request body '%s'", id, proposal.ID), http.StatusBadRequest)
+		errs := &utils.ValidationError{}
+		errs.Add("id", "proposal ID '%s' used as query param does not match ID in request body '%s'", id, proposal.ID)
+		utils.Invalid(w, errs)
 		return
 	}
 
-	if proposal.ID == "" {
-		proposal.ID = id
-	}
-
 	if err := validateProposal(&proposal); err != nil {
-		utils.Error(w, err.Error(), http.StatusBadRequest)
+		utils.Invalid(w, err)
 		return
 	}
 
+	recordsMu.Lock()
+	defer recordsMu.Unlock()
+
 	if !utils.Exists(proposal.ID, proposalsDataPath) {
-		utils.Error(w, fmt.Sprintf("proposal with ID '%s' was not found", proposal.ID), http.StatusBadRequest)
+		utils.Error(w, fmt.Sprintf("proposal with ID '%s' was not found", proposal.ID), http.StatusNotFound)
+		return
+	}
+
+	if !checkIfMatch(w, r, "proposal", proposal.ID, proposalsDataPath) {
 		return
 	}
 
 	writeProposal(w, r, &proposal)
 }
 
-// DeleteProposal deletes the file with data for a Speaker given their ID.
+// DeleteProposal deletes the file with data for a Speaker given their ID,
+// unless it does not match the If-Match header of the request.
 func DeleteProposal(w http.ResponseWriter, r *http.Request) {
 	id := mux.Vars(r)["id"]
 	if id == "" {
@@ -135,52 +184,76 @@ func DeleteProposal(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	recordsMu.Lock()
+	defer recordsMu.Unlock()
+
 	if !utils.Exists(id, proposalsDataPath) {
 		utils.Error(w, fmt.Sprintf("proposal with ID '%s' was not found", id), http.StatusNotFound)
 		return
 	}
 
+	if !checkIfMatch(w, r, "proposal", id, proposalsDataPath) {
+		return
+	}
+
 	if err := os.Remove(fmt.Sprintf("%s%s.json", proposalsDataPath, utils.MakeFileName(id))); err != nil {
 		utils.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	// The reviews of a deleted proposal are deleted with it
+	if err := os.RemoveAll(reviewsPath(id)); err != nil {
+		utils.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	w.WriteHeader(http.StatusOK)
 }
 
 func validateProposal(p *types.Proposal) error {
+	errs := &utils.ValidationError{}
 	if p.Type != types.SessionPresentationType && p.Type != types.LightningTalkType {
-		return fmt.Errorf("could not validate proposal's talk type; got: %s; want %s or %s", p.Type, types.SessionPresentationType, types.LightningTalkType)
+		errs.Add("type", "could not validate proposal's talk type; got: %s; want %s or %s", p.Type, types.SessionPresentationType, types.LightningTalkType)
 	}
 
-	if p.Submission.Status != types.Draft && p.Submission.Status != types.Final {
-		return fmt.Errorf("could not validate proposal's submission status; got: %s; want %s or %s", p.Type, types.Draft, types.Final)
+	if p.Submission.Status != types.Draft && p.Submission.Status != types.Final && p.Submission.Status != types.Withdrawn {
+		errs.Add("submission.status", "could not validate proposal's submission status; got: %s; want %s, %s or %s", p.Submission.Status, types.Draft, types.Final, types.Withdrawn)
 	}
 
+	// Proposals with a single speaker may only set the primary SpeakerID
+	if p.SpeakerID == "" && len(p.SpeakerIDs) > 0 {
+		p.SpeakerID = p.SpeakerIDs[0]
+	}
+	if len(p.SpeakerIDs) == 0 && p.SpeakerID != "" {
+		p.SpeakerIDs = []string{p.SpeakerID}
+	}
+
+	if p.ID == "" {
+		errs.Add("id", "proposal ID must be specified")
+	}
 	switch {
-	case p.ID == "":
-		return fmt.Errorf("proposal ID must be specified")
 	case p.SpeakerID == "":
-		return fmt.Errorf("speaker ID must be specified")
+		errs.Add("speakerID", "speaker ID must be specified")
+	case p.SpeakerIDs[0] != p.SpeakerID:
+		errs.Add("speakerIDs", "primary speaker ID '%s' must be the first of the speaker IDs", p.SpeakerID)
 	}
 
-	_, err := getSpeaker(p.SpeakerID)
-	if err != nil {
-		return fmt.Errorf("failed to get speaker: %v", err)
+	for _, speakerID := range p.SpeakerIDs {
+		if !utils.Exists(speakerID, speakerDataPath) {
+			errs.Add("speakerIDs", "could not find speaker with ID '%s'", speakerID)
+		}
 	}
 
 	if p.Submission.Status == types.Final {
-		switch {
-		case p.Title == "":
-			return fmt.Errorf("title must be specified")
-		case p.Abstract == "":
-			return fmt.Errorf("abstract must be specified")
-		case p.SpeakerID == "":
-			return fmt.Errorf("title must be specified")
+		if p.Title == "" {
+			errs.Add("title", "title must be specified")
+		}
+		if p.Abstract == "" {
+			errs.Add("abstract", "abstract must be specified")
 		}
 	}
 
-	return nil
+	return errs.OrNil()
 }
 
 func writeProposal(w http.ResponseWriter, r *http.Request, proposal *types.Proposal) {
@@ -192,6 +265,7 @@ func writeProposal(w http.ResponseWriter, r *http.Request, proposal *types.Propo
 	_ = os.MkdirAll(proposalsDataPath, 0755)
 	_ = os.WriteFile(fmt.Sprintf("%s%s.json", proposalsDataPath, utils.MakeFileName(proposal.ID)), content, 0644)
 
+	w.Header().Set("ETag", utils.ETag(content))
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(proposal)
 }
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/scottrigby/cfp-api/pkg/utils"
+)
+
+// recordsMu serializes the writes of the records, so that the If-Match
+// precondition of a write is checked against the record it replaces.
+var recordsMu sync.RWMutex
+
+// checkIfMatch writes a 412 Precondition Failed error and returns false if the
+// If-Match header of the request does not match the stored record.
+func checkIfMatch(w http.ResponseWriter, r *http.Request, kind, id, path string) bool {
+	if utils.IfMatch(r, utils.StoredETag(id, path)) {
+		return true
+	}
+	utils.Error(w, fmt.Sprintf("%s with ID '%s' has been modified", kind, id), http.StatusPreconditionFailed)
+	return false
+}
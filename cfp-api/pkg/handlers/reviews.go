@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/gorilla/mux"
+	"github.com/scottrigby/cfp-api/pkg/types"
+	"github.com/scottrigby/cfp-api/pkg/utils"
+)
+
+const reviewsDataPath = "data/reviews/"
+
+// reviewsPath returns the directory holding the reviews of a Proposal.
+func reviewsPath(proposalID string) string {
+	return fmt.Sprintf("%s%s/", reviewsDataPath, utils.MakeFileName(proposalID))
+}
+
+// CreateReview creates a new file with json data about a given Review of a Proposal.
+func CreateReview(w http.ResponseWriter, r *http.Request) {
+	var review types.Review
+	json.NewDecoder(r.Body).Decode(&review)
+
+	proposalID := mux.Vars(r)["id"]
+	if review.ProposalID == "" {
+		review.ProposalID = proposalID
+	}
+
+	if err := validateReview(&review, proposalID); err != nil {
+		utils.Invalid(w, err)
+		return
+	}
+
+	recordsMu.Lock()
+	defer recordsMu.Unlock()
+
+	if !utils.Exists(proposalID, proposalsDataPath) {
+		utils.Error(w, fmt.Sprintf("could not find proposal with ID '%s'", proposalID), http.StatusNotFound)
+		return
+	}
+
+	if utils.Exists(review.ID, reviewsPath(proposalID)) {
+		utils.Error(w, fmt.Sprintf("review with ID '%s' already exists", review.ID), http.StatusConflict)
+		return
+	}
+
+	writeReview(w, r, &review)
+}
+
+// GetReviewById returns the data for a Review of a Proposal given the Review's
+// ID, with its entity tag in the ETag header.
+func GetReviewById(w http.ResponseWriter, r *http.Request) {
+	proposalID, id := mux.Vars(r)["id"], mux.Vars(r)["reviewID"]
+	if id == "" {
+		utils.Error(w, "review ID must be specified", http.StatusBadRequest)
+		return
+	}
+
+	recordsMu.RLock()
+	defer recordsMu.RUnlock()
+
+	b, err := os.ReadFile(fmt.Sprintf("%s%s.json", reviewsPath(proposalID), utils.MakeFileName(id)))
+
+	switch {
+	case len(b) == 0:
+		utils.Error(w, fmt.Sprintf("could not find review with ID '%s'", id), http.StatusNotFound)
+		return
+	case err != nil:
+		utils.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var review types.Review
+	if err := json.Unmarshal(b, &review); err != nil {
+		utils.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("ETag", utils.ETag(b))
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(review)
+}
+
+// GetReviews returns a list with the data of all the Reviews of a Proposal.
+func GetReviews(w http.ResponseWriter, r *http.Request) {
+	proposalID := mux.Vars(r)["id"]
+	if !utils.Exists(proposalID, proposalsDataPath) {
+		utils.Error(w, fmt.Sprintf("could not find proposal with ID '%s'", proposalID), http.StatusNotFound)
+		return
+	}
+
+	files, err := os.ReadDir(reviewsPath(proposalID))
+	if err != nil && !os.IsNotExist(err) {
+		utils.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	reviewList := []types.Review{}
+	for _, file := range files {
+		b, err := os.ReadFile(fmt.Sprintf("%s%s", reviewsPath(proposalID), file.Name()))
+
+		switch {
+		case len(b) == 0:
+			continue
+		case err != nil:
+			utils.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		var review types.Review
+		if err := json.Unmarshal(b, &review); err != nil {
+			utils.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		reviewList = append(reviewList, review)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(reviewList)
+}
+
+// UpdateReview checks that a file for a Review exists given its ID
+// then replaces the data for that Review by overwriting it, unless it does
+// not match the If-Match header of the request.
+func UpdateReview(w http.ResponseWriter, r *http.Request) {
+	var review types.Review
+	json.NewDecoder(r.Body).Decode(&review)
+
+	proposalID, id := mux.Vars(r)["id"], mux.Vars(r)["reviewID"]
+	if id == "" {
+		utils.Error(w, "review ID must be specified", http.StatusBadRequest)
+		return
+	}
+
+	if review.ID == "" {
+		review.ID = id
+	}
+
+	if utils.MakeFileName(review.ID) != id {
+		errs := &utils.ValidationError{}
+		errs.Add("id", "review ID '%s' used as query param does not match ID in request body '%s'", id, review.ID)
+		utils.Invalid(w, errs)
+		return
+	}
+
+	if review.ProposalID == "" {
+		review.ProposalID = proposalID
+	}
+
+	if err := validateReview(&review, proposalID); err != nil {
+		utils.Invalid(w, err)
+		return
+	}
+
+	recordsMu.Lock()
+	defer recordsMu.Unlock()
+
+	if !utils.Exists(review.ID, reviewsPath(proposalID)) {
+		utils.Error(w, fmt.Sprintf("review with ID '%s' was not found", review.ID), http.StatusNotFound)
+		return
+	}
+
+	if !checkIfMatch(w, r, "review", review.ID, reviewsPath(proposalID)) {
+		return
+	}
+
+	writeReview(w, r, &review)
+}
+
+// DeleteReview deletes the file with data for a Review given its ID, unless
+// it does not match the If-Match header of the request.
+func DeleteReview(w http.ResponseWriter, r *http.Request) {
+	proposalID, id := mux.Vars(r)["id"], mux.Vars(r)["reviewID"]
+	if id == "" {
+		utils.Error(w, "review ID must be specified", http.StatusBadRequest)
+		return
+	}
+
+	recordsMu.Lock()
+	defer recordsMu.Unlock()
+
+	if !utils.Exists(id, reviewsPath(proposalID)) {
+		utils.Error(w, fmt.Sprintf("review with ID '%s' was not found", id), http.StatusNotFound)
+		return
+	}
+
+	if !checkIfMatch(w, r, "review", id, reviewsPath(proposalID)) {
+		return
+	}
+
+	if err := os.Remove(fmt.Sprintf("%s%s.json", reviewsPath(proposalID), utils.MakeFileName(id))); err != nil {
+		utils.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// validateReview validates the fields of a Review of the proposal with the
+// given ID, whose existence is checked by the handlers.
+func validateReview(review *types.Review, proposalID string) error {
+	errs := &utils.ValidationError{}
+	if review.ID == "" {
+		errs.Add("id", "review ID must be specified")
+	}
+	if utils.MakeFileName(review.ProposalID) != proposalID {
+		errs.Add("proposalID", "proposal ID '%s' used as query param does not match proposal ID in request body '%s'", proposalID, review.ProposalID)
+	}
+	if review.Reviewer == "" {
+		errs.Add("reviewer", "reviewer must be specified")
+	}
+	if review.Score < types.MinScore || review.Score > types.MaxScore {
+		errs.Add("score", "could not validate review's score; got: %d; want between %d and %d", review.Score, types.MinScore, types.MaxScore)
+	}
+
+	return errs.OrNil()
+}
+
+func writeReview(w http.ResponseWriter, r *http.Request, review *types.Review) {
+	path := reviewsPath(review.ProposalID)
+
+	content, _ := json.MarshalIndent(review, "", " ")
+	_ = os.MkdirAll(path, 0755)
+	_ = os.WriteFile(fmt.Sprintf("%s%s.json", path, utils.MakeFileName(review.ID)), content, 0644)
+
+	w.Header().Set("ETag", utils.ETag(content))
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(review)
+}
@@ -20,19 +20,23 @@ func CreateSpeaker(w http.ResponseWriter, r *http.Request) {
 	json.NewDecoder(r.Body).Decode(&speaker)
 
 	if err := validateSpeaker(&speaker); err != nil {
-		utils.Error(w, err.Error(), http.StatusBadRequest)
+		utils.Invalid(w, err)
 		return
 	}
 
+	recordsMu.Lock()
+	defer recordsMu.Unlock()
+
 	if utils.Exists(speaker.ID, speakerDataPath) {
-		utils.Error(w, fmt.Sprintf("speaker with ID '%s' already exists", speaker.ID), http.StatusBadRequest)
+		utils.Error(w, fmt.Sprintf("speaker with ID '%s' already exists", speaker.ID), http.StatusConflict)
 		return
 	}
 
 	writeSpeaker(w, r, &speaker)
 }
 
-// GetSpeakerById returns the data for a Speaker given the Speaker's ID.
+// GetSpeakerById returns the data for a Speaker given the Speaker's ID, with
+// its entity tag in the ETag header.
 func GetSpeakerById(w http.ResponseWriter, r *http.Request) {
 	id := mux.Vars(r)["id"]
 	if id == "" {
@@ -40,26 +44,41 @@ func GetSpeakerById(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	recordsMu.RLock()
+	defer recordsMu.RUnlock()
+
+	if !utils.Exists(id, speakerDataPath) {
+		utils.Error(w, fmt.Sprintf("could not find speaker with ID '%s'", id), http.StatusNotFound)
+		return
+	}
+
 	speaker, err := getSpeaker(id)
 	if err != nil {
-		utils.Error(w, err.Error(), http.StatusBadRequest)
+		utils.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	w.Header().Set("ETag", utils.StoredETag(id, speakerDataPath))
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(speaker)
 }
 
-// GetSpeakerById returns a list with the data of all the files of Speakers
-// contained in "data/speakers/".
+// GetSpeakers returns a page of the Speakers contained in "data/speakers/",
+// holding up to limit Speakers following the continue cursor.
 func GetSpeakers(w http.ResponseWriter, r *http.Request) {
+	opts, err := parseListOptions(r)
+	if err != nil {
+		utils.Invalid(w, err)
+		return
+	}
+
 	files, err := os.ReadDir(speakerDataPath)
 	if err != nil {
 		utils.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	var speakerList []types.Speaker
+	speakerList := []types.Speaker{}
 	for _, file := range files {
 		b, err := os.ReadFile(fmt.Sprintf("%s%s", speakerDataPath, file.Name()))
 
@@ -79,12 +98,16 @@ func GetSpeakers(w http.ResponseWriter, r *http.Request) {
 		speakerList = append(speakerList, speaker)
 	}
 
+	page := types.SpeakerList{}
+	page.Items, page.Continue = paginate(speakerList, func(s types.Speaker) string { return s.ID }, opts)
+
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(speakerList)
+	json.NewEncoder(w).Encode(page)
 }
 
 // UpdateSpeaker checks that a file for a Speaker exists given their ID
-// then replaces the json data for that Speaker by overwriting it.
+// then replaces the json data for that Speaker by overwriting it, unless it
+// does not match the If-Match header of the request.
 func UpdateSpeaker(w http.ResponseWriter, r *http.Request) {
 	var speaker types.Speaker
 	json.NewDecoder(r.Body).Decode(&speaker)
@@ -95,7 +118,9 @@ func UpdateSpeaker(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if utils.MakeFileName(speaker.ID) != id {
-		utils.Error(w, fmt.Sprintf("ID '%s' used as query param does not match ID in request body '%s'", id, speaker.ID), http.StatusBadRequest)
+		errs := &utils.ValidationError{}
+		errs.Add("id", "ID '%s' used as query param does not match ID in request body '%s'", id, speaker.ID)
+		utils.Invalid(w, errs)
 		return
 	}
 
@@ -104,19 +129,27 @@ func UpdateSpeaker(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := validateSpeaker(&speaker); err != nil {
-		utils.Error(w, err.Error(), http.StatusBadRequest)
+		utils.Invalid(w, err)
 		return
 	}
 
+	recordsMu.Lock()
+	defer recordsMu.Unlock()
+
 	if !utils.Exists(speaker.ID, speakerDataPath) {
 		utils.Error(w, fmt.Sprintf("speaker with ID '%s' was not found", id), http.StatusNotFound)
 		return
 	}
 
+	if !checkIfMatch(w, r, "speaker", speaker.ID, speakerDataPath) {
+		return
+	}
+
 	writeSpeaker(w, r, &speaker)
 }
 
-// DeleteSpeaker deletes the file with data for a Speaker given their ID.
+// DeleteSpeaker deletes the file with data for a Speaker given their ID,
+// unless it does not match the If-Match header of the request.
 func DeleteSpeaker(w http.ResponseWriter, r *http.Request) {
 	id := mux.Vars(r)["id"]
 	if id == "" {
@@ -124,11 +157,18 @@ func DeleteSpeaker(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	recordsMu.Lock()
+	defer recordsMu.Unlock()
+
 	if !utils.Exists(id, speakerDataPath) {
 		utils.Error(w, fmt.Sprintf("speaker with ID '%s' was not found", id), http.StatusNotFound)
 		return
 	}
 
+	if !checkIfMatch(w, r, "speaker", id, speakerDataPath) {
+		return
+	}
+
 	if err := os.Remove(fmt.Sprintf("%s%s.json", speakerDataPath, utils.MakeFileName(id))); err != nil {
 		utils.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -137,7 +177,8 @@ func DeleteSpeaker(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
-// writeSpeaker writes or overwrites a Speaker.
+// writeSpeaker writes or overwrites a Speaker, and returns it with its new
+// entity tag.
 func writeSpeaker(w http.ResponseWriter, r *http.Request, speaker *types.Speaker) {
 	speaker.Timestamp = time.Now()
 
@@ -147,16 +188,24 @@ func writeSpeaker(w http.ResponseWriter, r *http.Request, speaker *types.Speaker
 	_ = os.MkdirAll(speakerDataPath, 0755)
 	_ = os.WriteFile(fmt.Sprintf("%s%v.json", speakerDataPath, utils.MakeFileName(speaker.ID)), content, 0644)
 
+	w.Header().Set("ETag", utils.ETag(content))
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(speaker)
 }
 
 func validateSpeaker(speaker *types.Speaker) error {
-	if speaker.ID == "" || speaker.Name == "" || speaker.Email == "" {
-		return fmt.Errorf("speaker ID, Name and Email must be provided")
+	errs := &utils.ValidationError{}
+	if speaker.ID == "" {
+		errs.Add("id", "speaker ID must be provided")
+	}
+	if speaker.Name == "" {
+		errs.Add("name", "speaker name must be provided")
+	}
+	if speaker.Email == "" {
+		errs.Add("email", "speaker email must be provided")
 	}
 
-	return nil
+	return errs.OrNil()
 }
 
 func getSpeaker(id string) (*types.Speaker, error) {
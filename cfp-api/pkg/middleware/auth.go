@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/scottrigby/cfp-api/pkg/utils"
+)
+
+// Credentials are the credentials accepted by the API: a bearer token, and a
+// username and password for basic authentication. Empty credentials are not
+// accepted.
+type Credentials struct {
+	Token    string
+	Username string
+	Password string
+}
+
+// Enabled returns true if any credentials are set.
+func (c Credentials) Enabled() bool {
+	return c.Token != "" || c.Username != ""
+}
+
+// Auth returns a middleware rejecting the requests which do not carry one of
+// the credentials with a 401 Unauthorized status.
+func Auth(creds Credentials) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !creds.verify(r) {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="cfp-api", Basic realm="cfp-api"`)
+				utils.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func (c Credentials) verify(r *http.Request) bool {
+	if header := r.Header.Get("Authorization"); strings.HasPrefix(header, "Bearer ") && c.Token != "" {
+		return equal(strings.TrimPrefix(header, "Bearer "), c.Token)
+	}
+	if username, password, ok := r.BasicAuth(); ok && c.Username != "" {
+		// Compare both values, so that the time taken does not tell which
+		// one is wrong
+		usernameOK := equal(username, c.Username)
+		passwordOK := equal(password, c.Password)
+		return usernameOK && passwordOK
+	}
+	return false
+}
+
+func equal(got, want string) bool {
+	return subtle.ConstantTimeCompare([]byte(got), []byte(want)) == 1
+}
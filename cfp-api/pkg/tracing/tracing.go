@@ -0,0 +1,94 @@
+// Package tracing sets up the OpenTelemetry tracing of the CFP API, continuing
+// the trace context of the requests propagated with the W3C traceparent and
+// tracestate headers.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+)
+
+const (
+	// ExporterNone disables the export of the spans.
+	ExporterNone = "none"
+	// ExporterStdout writes the spans to the standard output.
+	ExporterStdout = "stdout"
+	// ExporterOTLP sends the spans to an OTLP/HTTP collector.
+	ExporterOTLP = "otlp"
+
+	// DefaultOTLPEndpoint is the default address of the OTLP/HTTP collector.
+	DefaultOTLPEndpoint = "http://localhost:4318"
+)
+
+// Options configure the export of the spans.
+type Options struct {
+	// Exporter is the exporter of the spans: none, stdout or otlp.
+	Exporter string
+	// Endpoint is the address of the OTLP/HTTP collector the spans are sent
+	// to with the otlp exporter, the spans being posted to /v1/traces.
+	Endpoint string
+}
+
+// Setup registers the tracer provider of the service, exporting its spans as
+// configured, and the W3C trace context propagator as the global ones. The
+// returned function flushes the pending spans and stops the export.
+func Setup(serviceName string, opts Options) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	exporter, err := newExporter(context.Background(), opts)
+	if err != nil {
+		return nil, err
+	}
+	if exporter == nil {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(serviceName))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// newExporter returns the exporter of the options, or nil if the export is
+// disabled.
+func newExporter(ctx context.Context, opts Options) (sdktrace.SpanExporter, error) {
+	switch opts.Exporter {
+	case "", ExporterNone:
+		return nil, nil
+	case ExporterStdout:
+		return stdouttrace.New()
+	case ExporterOTLP:
+		endpoint := opts.Endpoint
+		if endpoint == "" {
+			endpoint = DefaultOTLPEndpoint
+		}
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid OTLP endpoint '%s': %w", endpoint, err)
+		}
+
+		clientOpts := []otlptracehttp.Option{
+			otlptracehttp.WithEndpoint(u.Host),
+			otlptracehttp.WithURLPath(strings.TrimSuffix(u.Path, "/") + "/v1/traces"),
+		}
+		if u.Scheme != "https" {
+			clientOpts = append(clientOpts, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(ctx, clientOpts...)
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter '%s', must be one of %s, %s or %s",
+			opts.Exporter, ExporterNone, ExporterStdout, ExporterOTLP)
+	}
+}
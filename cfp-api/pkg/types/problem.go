@@ -0,0 +1,46 @@
+package types
+
+// ProblemContentType is the content type of the error responses of the API.
+const ProblemContentType = "application/problem+json"
+
+// Codes of the problems reported by the API, which are machine-readable
+// unlike their title and detail.
+const (
+	// CodeBadRequest reports a malformed request.
+	CodeBadRequest = "BadRequest"
+	// CodeValidationFailed reports a record with invalid fields, which are
+	// listed in the errors of the problem.
+	CodeValidationFailed = "ValidationFailed"
+	// CodeUnauthorized reports a request without valid credentials.
+	CodeUnauthorized = "Unauthorized"
+	// CodeNotFound reports a record, or the proposal of a review, which does
+	// not exist.
+	CodeNotFound = "NotFound"
+	// CodeAlreadyExists reports the creation of a record with the ID of an
+	// existing one.
+	CodeAlreadyExists = "AlreadyExists"
+	// CodePreconditionFailed reports a write of a record whose entity tag does
+	// not match the If-Match header of the request.
+	CodePreconditionFailed = "PreconditionFailed"
+	// CodeInternalError reports a failure of the API itself.
+	CodeInternalError = "InternalError"
+)
+
+// Problem is the body of the error responses of the API, an RFC 7807 problem
+// details object extended with the machine-readable Code of the problem and
+// the field Errors of an invalid record.
+type Problem struct {
+	Type   string       `json:"type,omitempty"`
+	Title  string       `json:"title"`
+	Status int          `json:"status"`
+	Detail string       `json:"detail,omitempty"`
+	Code   string       `json:"code"`
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError is the error of an invalid field of a record, named after its
+// JSON name.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
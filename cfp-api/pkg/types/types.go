@@ -1,3 +1,8 @@
+// Package types holds the schema of the records of the CFP API, shared by the
+// API server and its clients so that they encode the same JSON. The JSON
+// fields keep the names of the Go fields the records have always been encoded
+// with, lowercase names are read as well as they are matched
+// case-insensitively.
 package types
 
 import (
@@ -13,32 +18,77 @@ const (
 )
 
 // Speaker represents a speaker who is submitting a proposal.
+// Owner is an opaque marker set by the client which created the record.
+// ETag is the entity tag of the record as read or written by a client, which
+// is sent in the ETag and If-Match headers rather than in the JSON of the
+// record.
 type Speaker struct {
-	ID        string
-	Name      string
-	Bio       string
-	Email     string
-	Timestamp time.Time
+	ID        string    `json:"ID"`
+	Name      string    `json:"Name"`
+	Bio       string    `json:"Bio"`
+	Email     string    `json:"Email"`
+	Owner     string    `json:"Owner,omitempty"`
+	Timestamp time.Time `json:"Timestamp"`
+	ETag      string    `json:"-"`
+}
+
+// SpeakerList is a page of Speakers, sorted by ID. Continue is the cursor of
+// the next page, and is empty on the last page.
+type SpeakerList struct {
+	Items    []Speaker `json:"Items"`
+	Continue string    `json:"Continue,omitempty"`
 }
 
 // Proposal represents an instance of a proposed talk that is submitted to a CFP.
+// SpeakerIDs lists every speaker presenting the talk, starting with the
+// primary speaker SpeakerID. Owner is an opaque marker set by the client
+// which created the record. ETag is the entity tag of the record, as for
+// Speakers.
 type Proposal struct {
-	ID         string
-	Title      string
-	Abstract   string
-	Type       string
-	SpeakerID  string
-	Final      bool
-	Submission Submission
+	ID         string     `json:"ID"`
+	Title      string     `json:"Title"`
+	Abstract   string     `json:"Abstract"`
+	Type       string     `json:"Type"`
+	SpeakerID  string     `json:"SpeakerID"`
+	SpeakerIDs []string   `json:"SpeakerIDs"`
+	Final      bool       `json:"Final"`
+	Owner      string     `json:"Owner,omitempty"`
+	Submission Submission `json:"Submission"`
+	ETag       string     `json:"-"`
 }
 
+// ProposalList is a page of Proposals, sorted by ID. Continue is the cursor
+// of the next page, and is empty on the last page.
+type ProposalList struct {
+	Items    []Proposal `json:"Items"`
+	Continue string     `json:"Continue,omitempty"`
+}
+
+// Review represents the review of a Proposal by a member of the program committee.
+// Score ranges from MinScore to MaxScore. ETag is the entity tag of the
+// record, as for Speakers.
+type Review struct {
+	ID         string `json:"ID"`
+	ProposalID string `json:"ProposalID"`
+	Reviewer   string `json:"Reviewer"`
+	Score      int    `json:"Score"`
+	Comments   string `json:"Comments"`
+	ETag       string `json:"-"`
+}
+
+const (
+	MinScore = 1
+	MaxScore = 5
+)
+
 const (
-	Draft = "draft"
-	Final = "final"
+	Draft     = "draft"
+	Final     = "final"
+	Withdrawn = "withdrawn"
 )
 
 // Submission represents the status of a Proposal created by the user.
 type Submission struct {
-	LastUpdate time.Time
-	Status     string
+	LastUpdate time.Time `json:"LastUpdate"`
+	Status     string    `json:"Status"`
 }
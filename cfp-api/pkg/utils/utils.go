@@ -1,10 +1,15 @@
 package utils
 
 import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"strings"
+
+	"github.com/scottrigby/cfp-api/pkg/types"
 )
 
 func MakeFileName(ID string) string {
@@ -12,12 +17,111 @@ func MakeFileName(ID string) string {
 
 }
 
+// Error writes an error response with the given status, detailed by msg.
 func Error(w http.ResponseWriter, msg string, status int) {
-	w.WriteHeader(status)
-	w.Write([]byte(msg))
+	WriteProblem(w, &types.Problem{Status: status, Detail: msg})
+}
+
+// WriteProblem writes the problem as an application/problem+json response,
+// defaulting its title and code to the ones of its status.
+func WriteProblem(w http.ResponseWriter, problem *types.Problem) {
+	if problem.Title == "" {
+		problem.Title = http.StatusText(problem.Status)
+	}
+	if problem.Code == "" {
+		problem.Code = problemCode(problem.Status)
+	}
+
+	w.Header().Set("Content-Type", types.ProblemContentType)
+	w.WriteHeader(problem.Status)
+	json.NewEncoder(w).Encode(problem)
+}
+
+// Invalid writes a 400 Bad Request response for an invalid record, listing the
+// invalid fields of err if it is a ValidationError.
+func Invalid(w http.ResponseWriter, err error) {
+	problem := &types.Problem{Status: http.StatusBadRequest, Detail: err.Error(), Code: types.CodeValidationFailed}
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		problem.Errors = validationErr.Errors
+	}
+	WriteProblem(w, problem)
+}
+
+func problemCode(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return types.CodeBadRequest
+	case http.StatusUnauthorized:
+		return types.CodeUnauthorized
+	case http.StatusNotFound:
+		return types.CodeNotFound
+	case http.StatusConflict:
+		return types.CodeAlreadyExists
+	case http.StatusPreconditionFailed:
+		return types.CodePreconditionFailed
+	case http.StatusInternalServerError:
+		return types.CodeInternalError
+	default:
+		return strings.ReplaceAll(http.StatusText(status), " ", "")
+	}
+}
+
+// ValidationError is the error of a record with invalid fields.
+type ValidationError struct {
+	Errors []types.FieldError
+}
+
+// Add records the error of an invalid field.
+func (e *ValidationError) Add(field, format string, args ...interface{}) {
+	e.Errors = append(e.Errors, types.FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// OrNil returns the ValidationError if any field is invalid, or nil.
+func (e *ValidationError) OrNil() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *ValidationError) Error() string {
+	var messages []string
+	for _, fe := range e.Errors {
+		messages = append(messages, fmt.Sprintf("%s: %s", fe.Field, fe.Message))
+	}
+	return strings.Join(messages, "; ")
 }
 
 func Exists(id string, path string) bool {
 	b, _ := os.ReadFile(fmt.Sprintf("%s%s.json", path, MakeFileName(id)))
 	return len(b) > 0
 }
+
+// ETag returns the entity tag of the stored content of a record, which changes
+// whenever the record is written with another content.
+func ETag(content []byte) string {
+	sum := sha256.Sum256(content)
+	return fmt.Sprintf(`"%x"`, sum[:16])
+}
+
+// StoredETag returns the entity tag of the stored record with the given ID.
+func StoredETag(id string, path string) string {
+	b, _ := os.ReadFile(fmt.Sprintf("%s%s.json", path, MakeFileName(id)))
+	return ETag(b)
+}
+
+// IfMatch returns true if the request has no If-Match header, or if the
+// header lists the entity tag or "*".
+func IfMatch(r *http.Request, etag string) bool {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		return true
+	}
+	for _, tag := range strings.Split(header, ",") {
+		if tag = strings.TrimSpace(tag); tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -20,6 +20,24 @@ const (
 	// This is a "negative polarity" or "abnormal-true" type, and is only
 	// present on the resource if it is True.
 	FetchFailedCondition string = "FetchFailed"
+
+	// ReviewedCondition indicates whether a proposal has been reviewed enough
+	// times for the program committee to make a decision on it.
+	ReviewedCondition string = "Reviewed"
+
+	// DriftedCondition indicates that the record of an object in the CFP API
+	// was changed outside of the cluster, and has been corrected.
+	// It is removed once the record matches the object, or a new generation
+	// of the object is reconciled.
+	// This is a "negative polarity" or "abnormal-true" type, and is only
+	// present on the resource if it is True.
+	DriftedCondition string = "Drifted"
+
+	// DeletionBlockedCondition indicates that the deletion of an object is
+	// blocked by other objects still referencing it.
+	// This is a "negative polarity" or "abnormal-true" type, and is only
+	// present on the resource if it is True.
+	DeletionBlockedCondition string = "DeletionBlocked"
 )
 
 const (
@@ -31,4 +49,64 @@ const (
 
 	// FetchFailedReason indicates that the fetch failed.
 	FetchFailedReason string = "FetchFailed"
+
+	// DriftCorrectedReason indicates that the drifted fields of a record
+	// have been corrected.
+	DriftCorrectedReason string = "DriftCorrected"
+
+	// ReferencedByProposalsReason indicates that a Speaker can not be deleted
+	// because Proposals still reference it.
+	ReferencedByProposalsReason string = "ReferencedByProposals"
+
+	// ThrottledReason indicates that the CFP API is throttling the requests,
+	// or is unavailable, and that the request is retried later.
+	ThrottledReason string = "Throttled"
+
+	// AlreadyExistsReason indicates that the record of an object can not be
+	// created in the CFP API because a record with the same ID exists.
+	AlreadyExistsReason string = "AlreadyExists"
+
+	// SpeakerDeletedReason indicates that a Speaker referenced by a Proposal
+	// has been deleted, or is being deleted.
+	SpeakerDeletedReason string = "SpeakerDeleted"
+
+	// ValidationFailedReason indicates that the CFP API rejected the fields
+	// of the record of an object.
+	ValidationFailedReason string = "ValidationFailed"
+
+	// RecordNotFoundReason indicates that the record of an object, or one it
+	// refers to, was not found in the CFP API.
+	RecordNotFoundReason string = "RecordNotFound"
+)
+
+const (
+	// CFPNotOpenReason indicates that the call for papers of the conference
+	// is not open yet.
+	CFPNotOpenReason string = "CFPNotOpen"
+
+	// CFPClosedReason indicates that the call for papers of the conference
+	// is closed.
+	CFPClosedReason string = "CFPClosed"
+
+	// TalkTypeNotAcceptedReason indicates that the type of talk is not
+	// accepted by the call for papers of the conference.
+	TalkTypeNotAcceptedReason string = "TalkTypeNotAccepted"
+
+	// EndpointNotAllowedReason indicates that the CFP API endpoint of the
+	// conference is not one the controller is allowed to use.
+	EndpointNotAllowedReason string = "EndpointNotAllowed"
+
+	// EndpointMismatchReason indicates that a speaker of the proposal is
+	// recorded in another CFP API than the one of its conference.
+	EndpointMismatchReason string = "EndpointMismatch"
+)
+
+const (
+	// ReviewsPendingReason indicates that the proposal is waiting for more
+	// reviews before a decision can be made.
+	ReviewsPendingReason string = "ReviewsPending"
+
+	// DecisionReadyReason indicates that the proposal has been submitted as
+	// final and reviewed enough times for a decision to be made.
+	DecisionReadyReason string = "DecisionReady"
 )
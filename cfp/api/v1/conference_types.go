@@ -0,0 +1,104 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ConferenceIndexKey is the key used for indexing objects based on their
+	// referenced Conference.
+	ConferenceIndexKey = ".metadata.ConferenceName"
+)
+
+// ConferenceSpec defines the desired state of Conference
+type ConferenceSpec struct {
+	// OpenTime is the time at which the call for papers opens.
+	// Proposals are not submitted to the CFP API before that time.
+	// +required
+	OpenTime metav1.Time `json:"openTime"`
+
+	// CloseTime is the time at which the call for papers closes.
+	// Proposals can no longer be submitted as final after that time.
+	// +required
+	CloseTime metav1.Time `json:"closeTime"`
+
+	// TalkTypes are the types of talk accepted by the call for papers.
+	// All types are accepted when empty.
+	// +optional
+	TalkTypes []string `json:"talkTypes,omitempty"`
+
+	// Endpoint is the address of the CFP API collecting the proposals of this
+	// conference. It defaults to the CFP API address of the controller, and
+	// must otherwise be one of the endpoints the controller is allowed to use.
+	// The speakers of the proposals must reference a conference with the same
+	// endpoint, as their records are looked up by the CFP API.
+	// +kubebuilder:validation:Type=string
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// ConferenceRef is a reference to a Conference.
+type ConferenceRef struct {
+	// Name of conference custom resource
+	// +kubebuilder:validation:Type=string
+	Name string `json:"name"`
+
+	// Namespace of conference ref
+	// +kubebuilder:validation:Type=string
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// AcceptsTalkType returns true if talkType is accepted by the call for papers.
+func (c *Conference) AcceptsTalkType(talkType string) bool {
+	if len(c.Spec.TalkTypes) == 0 {
+		return true
+	}
+	for _, t := range c.Spec.TalkTypes {
+		if t == talkType {
+			return true
+		}
+	}
+	return false
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Open",type=string,JSONPath=`.spec.openTime`
+// +kubebuilder:printcolumn:name="Close",type=string,JSONPath=`.spec.closeTime`
+// +kubebuilder:printcolumn:name="Endpoint",type=string,JSONPath=`.spec.endpoint`
+// Conference is the Schema for the conferences API
+type Conference struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ConferenceSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ConferenceList contains a list of Conference
+type ConferenceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Conference `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Conference{}, &ConferenceList{})
+}
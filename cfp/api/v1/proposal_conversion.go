@@ -0,0 +1,140 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	talksv2 "github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/api/v2"
+)
+
+// ConversionDataAnnotation is the annotation used to store the parts of a
+// newer Proposal version that have no v1 representation (e.g. co-speakers),
+// so that a round trip through v1 does not lose them.
+const ConversionDataAnnotation = "talks.kubecon.na/conversion-data"
+
+// conversionData is the content of the ConversionDataAnnotation.
+type conversionData struct {
+	Spec talksv2.ProposalSpec `json:"spec"`
+}
+
+// ConvertTo converts this Proposal to the Hub version (v2).
+func (src *Proposal) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*talksv2.Proposal)
+	if !ok {
+		return fmt.Errorf("expected a v2 Proposal, got %T", dstRaw)
+	}
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+
+	// Restore the fields which could not be represented in v1, if any
+	restored := &conversionData{}
+	if data, ok := dst.Annotations[ConversionDataAnnotation]; ok {
+		if err := json.Unmarshal([]byte(data), restored); err != nil {
+			return fmt.Errorf("unable to restore conversion data: %w", err)
+		}
+		delete(dst.Annotations, ConversionDataAnnotation)
+		if len(dst.Annotations) == 0 {
+			dst.Annotations = nil
+		}
+	}
+	dst.Spec = restored.Spec
+
+	dst.Spec.Title = src.Spec.Title
+	dst.Spec.Abstract = src.Spec.Abstract
+	dst.Spec.Type = src.Spec.Type
+	dst.Spec.Final = src.Spec.Final
+	dst.Spec.Suspend = src.Spec.Suspend
+	dst.Spec.Interval = src.Spec.Interval
+	dst.Spec.DeletionPolicy = src.Spec.DeletionPolicy
+
+	// The v1 speaker is the primary speaker, co-speakers are kept as is
+	var coSpeakers []talksv2.SpeakerRef
+	if len(restored.Spec.SpeakerRefs) > 1 {
+		coSpeakers = restored.Spec.SpeakerRefs[1:]
+	}
+	dst.Spec.SpeakerRefs = nil
+	if src.Spec.SpeakerRef != nil {
+		dst.Spec.SpeakerRefs = append(dst.Spec.SpeakerRefs, talksv2.SpeakerRef{
+			Name:      src.Spec.SpeakerRef.Name,
+			Namespace: src.Spec.SpeakerRef.Namespace,
+		})
+	}
+	dst.Spec.SpeakerRefs = append(dst.Spec.SpeakerRefs, coSpeakers...)
+
+	dst.Status.ObservedGeneration = src.Status.ObservedGeneration
+	dst.Status.LastUpdate = src.Status.LastUpdate
+	dst.Status.LastSyncTime = src.Status.LastSyncTime
+	dst.Status.Submission = src.Status.Submission
+	dst.Status.Endpoint = src.Status.Endpoint
+	dst.Status.ReviewCount = src.Status.ReviewCount
+	dst.Status.MeanScore = src.Status.MeanScore
+	dst.Status.DecisionReady = src.Status.DecisionReady
+	dst.Status.Conditions = src.Status.Conditions
+
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v2) to this version.
+func (dst *Proposal) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*talksv2.Proposal)
+	if !ok {
+		return fmt.Errorf("expected a v2 Proposal, got %T", srcRaw)
+	}
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+
+	dst.Spec.Title = src.Spec.Title
+	dst.Spec.Abstract = src.Spec.Abstract
+	dst.Spec.Type = src.Spec.Type
+	dst.Spec.Final = src.Spec.Final
+	dst.Spec.Suspend = src.Spec.Suspend
+	dst.Spec.Interval = src.Spec.Interval
+	dst.Spec.DeletionPolicy = src.Spec.DeletionPolicy
+	dst.Spec.SpeakerRef = nil
+	if len(src.Spec.SpeakerRefs) > 0 {
+		dst.Spec.SpeakerRef = &SpeakerRef{
+			Name:      src.Spec.SpeakerRefs[0].Name,
+			Namespace: src.Spec.SpeakerRefs[0].Namespace,
+		}
+	}
+
+	dst.Status.ObservedGeneration = src.Status.ObservedGeneration
+	dst.Status.LastUpdate = src.Status.LastUpdate
+	dst.Status.LastSyncTime = src.Status.LastSyncTime
+	dst.Status.Submission = src.Status.Submission
+	dst.Status.Endpoint = src.Status.Endpoint
+	dst.Status.ReviewCount = src.Status.ReviewCount
+	dst.Status.MeanScore = src.Status.MeanScore
+	dst.Status.DecisionReady = src.Status.DecisionReady
+	dst.Status.Conditions = src.Status.Conditions
+
+	// Preserve the hub fields so that converting back to v2 is lossless
+	data, err := json.Marshal(conversionData{Spec: src.Spec})
+	if err != nil {
+		return fmt.Errorf("unable to store conversion data: %w", err)
+	}
+	if dst.Annotations == nil {
+		dst.Annotations = map[string]string{}
+	}
+	dst.Annotations[ConversionDataAnnotation] = string(data)
+
+	return nil
+}
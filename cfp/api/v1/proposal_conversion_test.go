@@ -0,0 +1,82 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	talksv2 "github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/api/v2"
+)
+
+func TestProposal_Conversion(t *testing.T) {
+	g := NewWithT(t)
+
+	hub := &talksv2.Proposal{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "proposal",
+			Namespace: "default",
+			Annotations: map[string]string{
+				"foo": "bar",
+			},
+		},
+		Spec: talksv2.ProposalSpec{
+			Title:    "this is a test proposal",
+			Abstract: "this is a test abstract",
+			Type:     "talk",
+			Final:    true,
+			Suspend:  true,
+			Interval: &metav1.Duration{Duration: time.Minute},
+			SpeakerRefs: []talksv2.SpeakerRef{
+				{Name: "speaker"},
+				{Name: "co-speaker", Namespace: "other"},
+			},
+		},
+		Status: talksv2.ProposalStatus{
+			ObservedGeneration: 2,
+			Submission:         ProposalStateFinal,
+			ReviewCount:        3,
+			MeanScore:          "4.33",
+			DecisionReady:      true,
+		},
+	}
+
+	// v2 -> v1 keeps the primary speaker
+	spoke := &Proposal{}
+	g.Expect(spoke.ConvertFrom(hub)).To(Succeed())
+	g.Expect(spoke.Spec.SpeakerRef).To(Equal(&SpeakerRef{Name: "speaker"}))
+	g.Expect(spoke.Spec.Title).To(Equal(hub.Spec.Title))
+	g.Expect(spoke.Spec.Suspend).To(BeTrue())
+	g.Expect(spoke.Status.Submission).To(Equal(hub.Status.Submission))
+	g.Expect(spoke.Status.MeanScore).To(Equal(hub.Status.MeanScore))
+	g.Expect(spoke.Annotations).To(HaveKey(ConversionDataAnnotation))
+	g.Expect(hub.Annotations).ToNot(HaveKey(ConversionDataAnnotation))
+
+	// v1 -> v2 restores the co-speakers
+	restored := &talksv2.Proposal{}
+	g.Expect(spoke.ConvertTo(restored)).To(Succeed())
+	g.Expect(restored).To(Equal(hub))
+
+	// a change of primary speaker in v1 keeps the co-speakers
+	spoke.Spec.SpeakerRef = &SpeakerRef{Name: "other-speaker"}
+	g.Expect(spoke.ConvertTo(restored)).To(Succeed())
+	g.Expect(restored.Spec.SpeakerRefs).To(Equal([]talksv2.SpeakerRef{
+		{Name: "other-speaker"},
+		{Name: "co-speaker", Namespace: "other"},
+	}))
+
+	// a v1 object without conversion data converts to a single speaker
+	spoke = &Proposal{
+		Spec: ProposalSpec{
+			Title:      "this is a test proposal",
+			SpeakerRef: &SpeakerRef{Name: "speaker", Namespace: "default"},
+		},
+	}
+	restored = &talksv2.Proposal{}
+	g.Expect(spoke.ConvertTo(restored)).To(Succeed())
+	g.Expect(restored.Spec.SpeakerRefs).To(Equal([]talksv2.SpeakerRef{
+		{Name: "speaker", Namespace: "default"},
+	}))
+	g.Expect(restored.Annotations).To(BeNil())
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package v1
 
 import (
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -25,6 +27,20 @@ const (
 	ProposalStateFinal = "final"
 	// ProposalStateDraft is the state of a proposal that is still being drafted
 	ProposalStateDraft = "draft"
+	// ProposalStateWithdrawn is the state of a proposal withdrawn by its speakers.
+	ProposalStateWithdrawn = "withdrawn"
+)
+
+const (
+	// DeletionPolicyDelete deletes the record of an object from the CFP API
+	// when the object is deleted.
+	DeletionPolicyDelete = "Delete"
+	// DeletionPolicyRetain keeps the record of an object in the CFP API when
+	// the object is deleted.
+	DeletionPolicyRetain = "Retain"
+	// DeletionPolicyWithdraw withdraws a proposal from the CFP API when the
+	// Proposal is deleted, keeping its record.
+	DeletionPolicyWithdraw = "Withdraw"
 )
 
 // ProposalSpec defines the desired state of Proposal
@@ -42,7 +58,7 @@ type ProposalSpec struct {
 	Abstract string `json:"abstract"`
 
 	// Type of talk the proposal is on.
-	// +kubebuilder:validation:Enum=talk;tutorial;keynote;lightning
+	// +kubebuilder:validation:Enum=talk;lightning
 	// +kubebuilder:default=talk
 	Type string `json:"type"`
 
@@ -52,6 +68,27 @@ type ProposalSpec struct {
 	// speaker submitting this talk
 	// +required
 	SpeakerRef *SpeakerRef `json:"speakerRef"`
+
+	// Suspend tells the controller to suspend the reconciliation of this
+	// object, no calls are made to the CFP API while it is suspended.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// Interval at which the object is compared with its record in the CFP API,
+	// to detect changes made to the record outside of the cluster.
+	// It defaults to the interval of the controller.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// DeletionPolicy defines what happens to the record of the proposal in the
+	// CFP API when the Proposal is deleted: Delete removes it, Retain keeps it
+	// and Withdraw marks it as withdrawn. When unset, draft proposals are
+	// deleted and final ones are retained.
+	// +kubebuilder:validation:Enum=Delete;Retain;Withdraw
+	// +optional
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
 }
 
 type SpeakerRef struct {
@@ -74,12 +111,36 @@ type ProposalStatus struct {
 	// +optional
 	LastUpdate metav1.Time `json:"lastUpdate,omitempty"`
 
+	// LastSyncTime is the last time the object was successfully synchronized
+	// with its record in the CFP API.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
 	// Submission represents the current status of the proposal
 	// It can be draft or final
 	// +kubebuilder:validation:Enum=draft;final
 	// +optional
 	Submission string `json:"submission,omitempty"`
 
+	// Endpoint is the address of the CFP API holding the record of the
+	// proposal.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// ReviewCount is the number of reviews of the proposal.
+	// +optional
+	ReviewCount int `json:"reviewCount,omitempty"`
+
+	// MeanScore is the mean score of the reviews of the proposal,
+	// with two decimals.
+	// +optional
+	MeanScore string `json:"meanScore,omitempty"`
+
+	// DecisionReady is true once the proposal has been submitted as final
+	// and reviewed enough times for the program committee to make a decision.
+	// +optional
+	DecisionReady bool `json:"decisionReady,omitempty"`
+
 	// Conditions is a list of conditions and their status.
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
@@ -104,6 +165,15 @@ func (p *Proposal) SetConditions(conditions []metav1.Condition) {
 	p.Status.Conditions = conditions
 }
 
+// GetRequeueAfter returns the duration after which the Proposal must be
+// reconciled again, or zero if it should use the default of the controller.
+func (p *Proposal) GetRequeueAfter() time.Duration {
+	if p.Spec.Interval == nil {
+		return 0
+	}
+	return p.Spec.Interval.Duration
+}
+
 //+kubebuilder:object:root=true
 
 // ProposalList contains a list of Proposal
@@ -0,0 +1,202 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	talksv2 "github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/api/v2"
+)
+
+// AcceptedProposalTypes are the types of talk accepted by the CFP API, which
+// must match the enum of the type in the spec of the Proposal.
+var AcceptedProposalTypes = []string{"talk", "lightning"}
+
+// SetupWebhookWithManager registers the Proposal validating webhook with the Manager.
+func (p *Proposal) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(p).
+		WithValidator(&ProposalValidator{Client: mgr.GetClient()}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-talks-kubecon-na-v1-proposal,mutating=false,failurePolicy=fail,sideEffects=None,groups=talks.kubecon.na,resources=proposals,verbs=create;update,versions=v1,name=vproposal.talks.kubecon.na,admissionReviewVersions=v1
+
+// ProposalValidator validates Proposals before they are admitted.
+// Proposals of every version are validated, as the API server converts them
+// to v1 before calling the webhook.
+// +kubebuilder:object:generate=false
+type ProposalValidator struct {
+	Client client.Reader
+}
+
+var _ webhook.CustomValidator = &ProposalValidator{}
+
+// ValidateCreate rejects Proposals referencing unknown speakers or conference,
+// or with a type of talk the CFP API does not accept.
+func (v *ProposalValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	proposal, err := toHubProposal(obj)
+	if err != nil {
+		return err
+	}
+
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, validateProposalType(proposal)...)
+	allErrs = append(allErrs, v.validateSpeakerRefs(ctx, proposal)...)
+	allErrs = append(allErrs, v.validateConferenceRef(ctx, proposal)...)
+
+	return invalidProposal(proposal, allErrs)
+}
+
+// ValidateUpdate rejects the same Proposals as ValidateCreate, and any change
+// to the content of a Proposal which has been submitted as final.
+// Only the fields that changed are validated, so that a Proposal whose
+// speaker went away can still be updated, e.g. to remove its finalizer.
+func (v *ProposalValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	oldProposal, err := toHubProposal(oldObj)
+	if err != nil {
+		return err
+	}
+	proposal, err := toHubProposal(newObj)
+	if err != nil {
+		return err
+	}
+
+	if !proposal.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	if oldProposal.Status.Submission == ProposalStateFinal && !equality.Semantic.DeepEqual(submittedSpec(oldProposal), submittedSpec(proposal)) {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"),
+			"the proposal has been submitted as final and can no longer be changed"))
+	}
+	if oldProposal.Spec.Type != proposal.Spec.Type {
+		allErrs = append(allErrs, validateProposalType(proposal)...)
+	}
+	if !equality.Semantic.DeepEqual(oldProposal.Spec.SpeakerRefs, proposal.Spec.SpeakerRefs) {
+		allErrs = append(allErrs, v.validateSpeakerRefs(ctx, proposal)...)
+	}
+	if !equality.Semantic.DeepEqual(oldProposal.Spec.ConferenceRef, proposal.Spec.ConferenceRef) {
+		allErrs = append(allErrs, v.validateConferenceRef(ctx, proposal)...)
+	}
+
+	return invalidProposal(proposal, allErrs)
+}
+
+// ValidateDelete allows all deletions.
+func (v *ProposalValidator) ValidateDelete(_ context.Context, _ runtime.Object) error {
+	return nil
+}
+
+func (v *ProposalValidator) validateSpeakerRefs(ctx context.Context, proposal *talksv2.Proposal) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, ref := range proposal.Spec.SpeakerRefs {
+		// The primary speaker is the v1 speakerRef
+		path := field.NewPath("spec", "speakerRef")
+		if i > 0 {
+			path = field.NewPath("spec", "speakerRefs").Index(i)
+		}
+
+		namespacedName := types.NamespacedName{Namespace: proposal.Namespace, Name: ref.Name}
+		if ref.Namespace != "" {
+			namespacedName.Namespace = ref.Namespace
+		}
+
+		if err := v.Client.Get(ctx, namespacedName, &Speaker{}); err != nil {
+			if apierrors.IsNotFound(err) {
+				allErrs = append(allErrs, field.NotFound(path, namespacedName.String()))
+				continue
+			}
+			allErrs = append(allErrs, field.InternalError(path, fmt.Errorf("unable to get speaker %s: %w", namespacedName.String(), err)))
+		}
+	}
+	return allErrs
+}
+
+func (v *ProposalValidator) validateConferenceRef(ctx context.Context, proposal *talksv2.Proposal) field.ErrorList {
+	ref := proposal.Spec.ConferenceRef
+	if ref == nil {
+		return nil
+	}
+
+	path := field.NewPath("spec", "conferenceRef")
+	namespacedName := types.NamespacedName{Namespace: proposal.Namespace, Name: ref.Name}
+	if ref.Namespace != "" {
+		namespacedName.Namespace = ref.Namespace
+	}
+
+	if err := v.Client.Get(ctx, namespacedName, &Conference{}); err != nil {
+		if apierrors.IsNotFound(err) {
+			return field.ErrorList{field.NotFound(path, namespacedName.String())}
+		}
+		return field.ErrorList{field.InternalError(path, fmt.Errorf("unable to get conference %s: %w", namespacedName.String(), err))}
+	}
+	return nil
+}
+
+func validateProposalType(proposal *talksv2.Proposal) field.ErrorList {
+	for _, t := range AcceptedProposalTypes {
+		if proposal.Spec.Type == t {
+			return nil
+		}
+	}
+	return field.ErrorList{field.NotSupported(field.NewPath("spec", "type"), proposal.Spec.Type, AcceptedProposalTypes)}
+}
+
+// submittedSpec returns the spec of the Proposal without the fields which do
+// not change its submission to the CFP API, such as suspend, interval and
+// deletionPolicy.
+func submittedSpec(proposal *talksv2.Proposal) talksv2.ProposalSpec {
+	spec := *proposal.Spec.DeepCopy()
+	spec.Suspend = false
+	spec.Interval = nil
+	spec.DeletionPolicy = ""
+	return spec
+}
+
+// toHubProposal converts a v1 Proposal to the hub version, which holds all
+// the speakers of the Proposal.
+func toHubProposal(obj runtime.Object) (*talksv2.Proposal, error) {
+	p, ok := obj.(*Proposal)
+	if !ok {
+		return nil, fmt.Errorf("expected a Proposal, got %T", obj)
+	}
+
+	hub := &talksv2.Proposal{}
+	if err := p.ConvertTo(hub); err != nil {
+		return nil, err
+	}
+	return hub, nil
+}
+
+func invalidProposal(proposal *talksv2.Proposal, allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("Proposal").GroupKind(), proposal.Name, allErrs)
+}
@@ -0,0 +1,34 @@
+package v1
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func TestAcceptedProposalTypes(t *testing.T) {
+	g := NewWithT(t)
+
+	data, err := os.ReadFile("../../config/crd/bases/talks.kubecon.na_proposals.yaml")
+	g.Expect(err).ToNot(HaveOccurred())
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	g.Expect(yaml.Unmarshal(data, crd)).To(Succeed())
+
+	// The types accepted by the webhook are the ones of the schema of every
+	// version of the Proposal
+	g.Expect(crd.Spec.Versions).ToNot(BeEmpty())
+	for _, version := range crd.Spec.Versions {
+		schema := version.Schema.OpenAPIV3Schema.Properties["spec"].Properties["type"]
+		var types []string
+		for _, e := range schema.Enum {
+			var t string
+			g.Expect(json.Unmarshal(e.Raw, &t)).To(Succeed())
+			types = append(types, t)
+		}
+		g.Expect(types).To(ConsistOf(AcceptedProposalTypes), "version %s", version.Name)
+	}
+}
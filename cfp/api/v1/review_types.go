@@ -0,0 +1,120 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ProposalIndexKey is the key used for indexing objects based on their
+	// referenced Proposal.
+	ProposalIndexKey = ".metadata.ProposalName"
+)
+
+// ReviewSpec defines the desired state of Review
+type ReviewSpec struct {
+	// Reviewer is the member of the program committee reviewing the proposal.
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Reviewer string `json:"reviewer"`
+
+	// Score given to the proposal, from 1 to 5.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=5
+	// +required
+	Score int `json:"score"`
+
+	// Comments of the reviewer on the proposal.
+	// +optional
+	Comments string `json:"comments,omitempty"`
+
+	// ProposalRef is the proposal being reviewed.
+	// +required
+	ProposalRef ProposalRef `json:"proposalRef"`
+}
+
+type ProposalRef struct {
+	// Name of proposal custom resource
+	// +kubebuilder:validation:Type=string
+	Name string `json:"name"`
+
+	// Namespace of proposal ref
+	// +kubebuilder:validation:Type=string
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ReviewStatus defines the observed state of Review
+type ReviewStatus struct {
+	// ObservedGeneration is the last observed generation of the Review object.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// ID is the review ID
+	// in the form of namespace-name
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// Endpoint is the address of the CFP API holding the record of the
+	// review.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// ProposalID is the ID of the proposal the review has been pushed to.
+	// +optional
+	ProposalID string `json:"proposalID,omitempty"`
+
+	// Conditions is a list of conditions and their status.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Proposal",type=string,JSONPath=`.spec.proposalRef.name`
+// +kubebuilder:printcolumn:name="Reviewer",type=string,JSONPath=`.spec.reviewer`
+// +kubebuilder:printcolumn:name="Score",type=integer,JSONPath=`.spec.score`
+// Review is the Schema for the reviews API
+type Review struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ReviewSpec   `json:"spec,omitempty"`
+	Status ReviewStatus `json:"status,omitempty"`
+}
+
+func (r *Review) GetConditions() []metav1.Condition {
+	return r.Status.Conditions
+}
+
+func (r *Review) SetConditions(conditions []metav1.Condition) {
+	r.Status.Conditions = conditions
+}
+
+//+kubebuilder:object:root=true
+
+// ReviewList contains a list of Review
+type ReviewList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Review `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Review{}, &ReviewList{})
+}
@@ -17,7 +17,11 @@ limitations under the License.
 package v1
 
 import (
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fluxcd/pkg/apis/acl"
 )
 
 const (
@@ -40,6 +44,46 @@ type SpeakerSpec struct {
 	// +kubebuilder:validation:Type=string
 	// +kubebuilder:validation:Pattern="^[a-zA-Z0-9.-]+@([a-zA-Z0-9]+.)+[a-zA-Z0-9-]{2,15}$"
 	Email string `json:"email,omitempty"`
+
+	// ConferenceRef is the conference whose CFP API holds the record of the
+	// speaker, which defaults to the CFP API of the controller. The speaker
+	// can only present proposals submitted to a conference with the same CFP
+	// API. It can not be changed once the record has been created.
+	// +optional
+	ConferenceRef *ConferenceRef `json:"conferenceRef,omitempty"`
+
+	// Suspend tells the controller to suspend the reconciliation of this
+	// object, no calls are made to the CFP API while it is suspended.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// Interval at which the object is compared with its record in the CFP API,
+	// to detect changes made to the record outside of the cluster.
+	// It defaults to the interval of the controller.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// AccessFrom defines the namespaces whose Proposals are allowed to
+	// reference this Speaker. Proposals of other namespaces than the one of the
+	// Speaker are denied access when empty.
+	// +optional
+	AccessFrom *acl.AccessFrom `json:"accessFrom,omitempty"`
+
+	// ImportID is the ID of an existing record of the speaker in the CFP API.
+	// The record is adopted by the Speaker instead of being created, and
+	// converges to its spec. It is ignored once the Speaker has an ID.
+	// +kubebuilder:validation:Pattern="^[a-zA-Z0-9._-]+$"
+	// +optional
+	ImportID string `json:"importID,omitempty"`
+
+	// DeletionPolicy defines what happens to the record of the speaker in the
+	// CFP API when the Speaker is deleted: Delete removes it and Retain keeps it.
+	// +kubebuilder:validation:Enum=Delete;Retain
+	// +kubebuilder:default=Delete
+	// +optional
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
 }
 
 // SpeakerStatus defines the observed state of Speaker
@@ -53,6 +97,16 @@ type SpeakerStatus struct {
 	// +optional
 	ID string `json:"id,omitempty"`
 
+	// Endpoint is the address of the CFP API holding the record of the
+	// speaker.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// LastSyncTime is the last time the object was successfully synchronized
+	// with its record in the CFP API.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
 	// Conditions is a list of conditions and their status.
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
@@ -79,6 +133,15 @@ func (s *Speaker) SetConditions(conditions []metav1.Condition) {
 	s.Status.Conditions = conditions
 }
 
+// GetRequeueAfter returns the duration after which the Speaker must be
+// reconciled again, or zero if it should use the default of the controller.
+func (s *Speaker) GetRequeueAfter() time.Duration {
+	if s.Spec.Interval == nil {
+		return 0
+	}
+	return s.Spec.Interval.Duration
+}
+
 //+kubebuilder:object:root=true
 
 // SpeakerList contains a list of Speaker
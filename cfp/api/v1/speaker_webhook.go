@@ -0,0 +1,155 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// SetupWebhookWithManager registers the Speaker validating webhook with the Manager.
+func (s *Speaker) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(s).
+		WithValidator(&SpeakerValidator{Client: mgr.GetClient()}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-talks-kubecon-na-v1-speaker,mutating=false,failurePolicy=fail,sideEffects=None,groups=talks.kubecon.na,resources=speakers,verbs=create;update,versions=v1,name=vspeaker.talks.kubecon.na,admissionReviewVersions=v1
+
+// SpeakerValidator validates Speakers before they are admitted.
+// +kubebuilder:object:generate=false
+type SpeakerValidator struct {
+	Client client.Reader
+}
+
+var _ webhook.CustomValidator = &SpeakerValidator{}
+
+// ValidateCreate rejects Speakers whose email is already used by another
+// Speaker, or referencing an unknown conference.
+func (v *SpeakerValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	speaker, ok := obj.(*Speaker)
+	if !ok {
+		return fmt.Errorf("expected a Speaker, got %T", obj)
+	}
+
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, v.validateEmail(ctx, speaker)...)
+	allErrs = append(allErrs, v.validateConferenceRef(ctx, speaker)...)
+
+	return invalidSpeaker(speaker, allErrs)
+}
+
+// ValidateUpdate rejects Speakers whose email is changed to one already used
+// by another Speaker, and any change to the conference of a Speaker whose
+// record has been created, as the record would be left in the CFP API of the
+// previous conference.
+func (v *SpeakerValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	oldSpeaker, ok := oldObj.(*Speaker)
+	if !ok {
+		return fmt.Errorf("expected a Speaker, got %T", oldObj)
+	}
+	speaker, ok := newObj.(*Speaker)
+	if !ok {
+		return fmt.Errorf("expected a Speaker, got %T", newObj)
+	}
+
+	if !speaker.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	if !strings.EqualFold(oldSpeaker.Spec.Email, speaker.Spec.Email) {
+		allErrs = append(allErrs, v.validateEmail(ctx, speaker)...)
+	}
+	if !equality.Semantic.DeepEqual(oldSpeaker.Spec.ConferenceRef, speaker.Spec.ConferenceRef) {
+		if oldSpeaker.Status.ID != "" {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "conferenceRef"),
+				"the speaker has been recorded in the CFP API of its conference and can no longer change conference"))
+		} else {
+			allErrs = append(allErrs, v.validateConferenceRef(ctx, speaker)...)
+		}
+	}
+
+	return invalidSpeaker(speaker, allErrs)
+}
+
+// ValidateDelete allows all deletions.
+func (v *SpeakerValidator) ValidateDelete(_ context.Context, _ runtime.Object) error {
+	return nil
+}
+
+func (v *SpeakerValidator) validateEmail(ctx context.Context, speaker *Speaker) field.ErrorList {
+	if speaker.Spec.Email == "" {
+		return nil
+	}
+
+	path := field.NewPath("spec", "email")
+
+	var list SpeakerList
+	if err := v.Client.List(ctx, &list); err != nil {
+		return field.ErrorList{field.InternalError(path, fmt.Errorf("unable to list speakers: %w", err))}
+	}
+
+	for _, s := range list.Items {
+		if s.Namespace == speaker.Namespace && s.Name == speaker.Name {
+			continue
+		}
+		if strings.EqualFold(s.Spec.Email, speaker.Spec.Email) {
+			return field.ErrorList{field.Duplicate(path, fmt.Sprintf("%s (used by speaker %s/%s)", speaker.Spec.Email, s.Namespace, s.Name))}
+		}
+	}
+	return nil
+}
+
+func (v *SpeakerValidator) validateConferenceRef(ctx context.Context, speaker *Speaker) field.ErrorList {
+	ref := speaker.Spec.ConferenceRef
+	if ref == nil {
+		return nil
+	}
+
+	path := field.NewPath("spec", "conferenceRef")
+	namespacedName := types.NamespacedName{Namespace: speaker.Namespace, Name: ref.Name}
+	if ref.Namespace != "" {
+		namespacedName.Namespace = ref.Namespace
+	}
+
+	if err := v.Client.Get(ctx, namespacedName, &Conference{}); err != nil {
+		if apierrors.IsNotFound(err) {
+			return field.ErrorList{field.NotFound(path, namespacedName.String())}
+		}
+		return field.ErrorList{field.InternalError(path, fmt.Errorf("unable to get conference %s: %w", namespacedName.String(), err))}
+	}
+	return nil
+}
+
+func invalidSpeaker(speaker *Speaker, allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("Speaker").GroupKind(), speaker.Name, allErrs)
+}
@@ -22,10 +22,106 @@ limitations under the License.
 package v1
 
 import (
+	"github.com/fluxcd/pkg/apis/acl"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Conference) DeepCopyInto(out *Conference) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Conference.
+func (in *Conference) DeepCopy() *Conference {
+	if in == nil {
+		return nil
+	}
+	out := new(Conference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Conference) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConferenceList) DeepCopyInto(out *ConferenceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Conference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConferenceList.
+func (in *ConferenceList) DeepCopy() *ConferenceList {
+	if in == nil {
+		return nil
+	}
+	out := new(ConferenceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ConferenceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConferenceRef) DeepCopyInto(out *ConferenceRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConferenceRef.
+func (in *ConferenceRef) DeepCopy() *ConferenceRef {
+	if in == nil {
+		return nil
+	}
+	out := new(ConferenceRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConferenceSpec) DeepCopyInto(out *ConferenceSpec) {
+	*out = *in
+	in.OpenTime.DeepCopyInto(&out.OpenTime)
+	in.CloseTime.DeepCopyInto(&out.CloseTime)
+	if in.TalkTypes != nil {
+		in, out := &in.TalkTypes, &out.TalkTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConferenceSpec.
+func (in *ConferenceSpec) DeepCopy() *ConferenceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ConferenceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Proposal) DeepCopyInto(out *Proposal) {
 	*out = *in
@@ -85,6 +181,21 @@ func (in *ProposalList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProposalRef) DeepCopyInto(out *ProposalRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProposalRef.
+func (in *ProposalRef) DeepCopy() *ProposalRef {
+	if in == nil {
+		return nil
+	}
+	out := new(ProposalRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProposalSpec) DeepCopyInto(out *ProposalSpec) {
 	*out = *in
@@ -93,6 +204,11 @@ func (in *ProposalSpec) DeepCopyInto(out *ProposalSpec) {
 		*out = new(SpeakerRef)
 		**out = **in
 	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProposalSpec.
@@ -109,6 +225,10 @@ func (in *ProposalSpec) DeepCopy() *ProposalSpec {
 func (in *ProposalStatus) DeepCopyInto(out *ProposalStatus) {
 	*out = *in
 	in.LastUpdate.DeepCopyInto(&out.LastUpdate)
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]metav1.Condition, len(*in))
@@ -129,7 +249,7 @@ func (in *ProposalStatus) DeepCopy() *ProposalStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Speaker) DeepCopyInto(out *Speaker) {
+func (in *Review) DeepCopyInto(out *Review) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -137,6 +257,103 @@ func (in *Speaker) DeepCopyInto(out *Speaker) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Review.
+func (in *Review) DeepCopy() *Review {
+	if in == nil {
+		return nil
+	}
+	out := new(Review)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Review) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReviewList) DeepCopyInto(out *ReviewList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Review, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReviewList.
+func (in *ReviewList) DeepCopy() *ReviewList {
+	if in == nil {
+		return nil
+	}
+	out := new(ReviewList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ReviewList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReviewSpec) DeepCopyInto(out *ReviewSpec) {
+	*out = *in
+	out.ProposalRef = in.ProposalRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReviewSpec.
+func (in *ReviewSpec) DeepCopy() *ReviewSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ReviewSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReviewStatus) DeepCopyInto(out *ReviewStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReviewStatus.
+func (in *ReviewStatus) DeepCopy() *ReviewStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ReviewStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Speaker) DeepCopyInto(out *Speaker) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Speaker.
 func (in *Speaker) DeepCopy() *Speaker {
 	if in == nil {
@@ -205,6 +422,21 @@ func (in *SpeakerRef) DeepCopy() *SpeakerRef {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SpeakerSpec) DeepCopyInto(out *SpeakerSpec) {
 	*out = *in
+	if in.ConferenceRef != nil {
+		in, out := &in.ConferenceRef, &out.ConferenceRef
+		*out = new(ConferenceRef)
+		**out = **in
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.AccessFrom != nil {
+		in, out := &in.AccessFrom, &out.AccessFrom
+		*out = new(acl.AccessFrom)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpeakerSpec.
@@ -220,6 +452,10 @@ func (in *SpeakerSpec) DeepCopy() *SpeakerSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SpeakerStatus) DeepCopyInto(out *SpeakerStatus) {
 	*out = *in
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]metav1.Condition, len(*in))
@@ -0,0 +1,189 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ProposalSpec defines the desired state of Proposal
+type ProposalSpec struct {
+	// Title of the proposal
+	// +kubebuilder:validation:MaxLength=50
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Title string `json:"title"`
+
+	// Abstract on what the proposal is about
+	// +kubebuilder:validation:MaxLength=50
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Abstract string `json:"abstract"`
+
+	// Type of talk the proposal is on.
+	// +kubebuilder:validation:Enum=talk;lightning
+	// +kubebuilder:default=talk
+	Type string `json:"type"`
+
+	// +required
+	Final bool `json:"final"`
+
+	// SpeakerRefs are the speakers presenting this talk.
+	// The first entry is the primary speaker, any following entries are
+	// co-speakers.
+	// +kubebuilder:validation:MinItems=1
+	// +required
+	SpeakerRefs []SpeakerRef `json:"speakerRefs"`
+
+	// ConferenceRef is the conference the proposal is submitted to.
+	// Its call for papers window and accepted types of talk apply to the
+	// proposal.
+	// +optional
+	ConferenceRef *ConferenceRef `json:"conferenceRef,omitempty"`
+
+	// Suspend tells the controller to suspend the reconciliation of this
+	// object, no calls are made to the CFP API while it is suspended.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// Interval at which the object is compared with its record in the CFP API,
+	// to detect changes made to the record outside of the cluster.
+	// It defaults to the interval of the controller.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// DeletionPolicy defines what happens to the record of the proposal in the
+	// CFP API when the Proposal is deleted: Delete removes it, Retain keeps it
+	// and Withdraw marks it as withdrawn. When unset, draft proposals are
+	// deleted and final ones are retained.
+	// +kubebuilder:validation:Enum=Delete;Retain;Withdraw
+	// +optional
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+}
+
+type SpeakerRef struct {
+	// Name of speaker custom resource
+	// +kubebuilder:validation:Type=string
+	Name string `json:"name"`
+
+	// Namespace of speaker ref
+	// +kubebuilder:validation:Type=string
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+type ConferenceRef struct {
+	// Name of conference custom resource
+	// +kubebuilder:validation:Type=string
+	Name string `json:"name"`
+
+	// Namespace of conference ref
+	// +kubebuilder:validation:Type=string
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ProposalStatus defines the observed state of Proposal
+type ProposalStatus struct {
+	// ObservedGeneration is the last observed generation of the Speaker object.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// The time at which the proposal was submitted
+	// +optional
+	LastUpdate metav1.Time `json:"lastUpdate,omitempty"`
+
+	// LastSyncTime is the last time the object was successfully synchronized
+	// with its record in the CFP API.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// Submission represents the current status of the proposal
+	// It can be draft or final
+	// +kubebuilder:validation:Enum=draft;final
+	// +optional
+	Submission string `json:"submission,omitempty"`
+
+	// Endpoint is the address of the CFP API holding the record of the
+	// proposal.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// ReviewCount is the number of reviews of the proposal.
+	// +optional
+	ReviewCount int `json:"reviewCount,omitempty"`
+
+	// MeanScore is the mean score of the reviews of the proposal,
+	// with two decimals.
+	// +optional
+	MeanScore string `json:"meanScore,omitempty"`
+
+	// DecisionReady is true once the proposal has been submitted as final
+	// and reviewed enough times for the program committee to make a decision.
+	// +optional
+	DecisionReady bool `json:"decisionReady,omitempty"`
+
+	// Conditions is a list of conditions and their status.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// Proposal is the Schema for the proposals API
+type Proposal struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ProposalSpec   `json:"spec,omitempty"`
+	Status ProposalStatus `json:"status,omitempty"`
+}
+
+func (p *Proposal) GetConditions() []metav1.Condition {
+	return p.Status.Conditions
+}
+
+func (p *Proposal) SetConditions(conditions []metav1.Condition) {
+	p.Status.Conditions = conditions
+}
+
+// GetRequeueAfter returns the duration after which the Proposal must be
+// reconciled again, or zero if it should use the default of the controller.
+func (p *Proposal) GetRequeueAfter() time.Duration {
+	if p.Spec.Interval == nil {
+		return 0
+	}
+	return p.Spec.Interval.Duration
+}
+
+//+kubebuilder:object:root=true
+
+// ProposalList contains a list of Proposal
+type ProposalList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Proposal `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Proposal{}, &ProposalList{})
+}
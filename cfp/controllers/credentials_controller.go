@@ -0,0 +1,114 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/internal/cfp"
+)
+
+const (
+	// TokenKey is the key of the bearer token in the credentials Secret.
+	TokenKey = "token"
+	// UsernameKey and PasswordKey are the keys of the basic authentication
+	// credentials in the credentials Secret.
+	UsernameKey = "username"
+	PasswordKey = "password"
+)
+
+// CredentialsReconciler loads the credentials of the CFP API from a Secret
+// into the store shared by the clients of the reconcilers, every time the
+// Secret changes.
+type CredentialsReconciler struct {
+	client.Client
+	EventRecorder record.EventRecorder
+	Store         *cfp.CredentialStore
+	SecretRef     types.NamespacedName
+}
+
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+func (r *CredentialsReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, req.NamespacedName, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			// Stop sending the deleted credentials
+			r.Store.Set(cfp.Credentials{})
+			log.Info("credentials Secret not found, requests to the CFP API are not authenticated")
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	creds, err := credentialsFromSecret(secret)
+	if err != nil {
+		// Keep the previous credentials until the Secret is fixed, which
+		// reconciles it again
+		log.Error(err, "invalid credentials Secret")
+		r.EventRecorder.Event(secret, corev1.EventTypeWarning, invalidCredentialsReason,
+			fmt.Sprintf("%s, the previous credentials are still used", err))
+		return ctrl.Result{}, nil
+	}
+	r.Store.Set(creds)
+	log.Info("loaded the CFP API credentials")
+	return ctrl.Result{}, nil
+}
+
+// credentialsFromSecret returns the token, or the username and password held
+// by the Secret.
+func credentialsFromSecret(secret *corev1.Secret) (cfp.Credentials, error) {
+	creds := cfp.Credentials{
+		Token:    string(secret.Data[TokenKey]),
+		Username: string(secret.Data[UsernameKey]),
+		Password: string(secret.Data[PasswordKey]),
+	}
+	switch {
+	case creds.Token != "":
+		return creds, nil
+	case creds.Username != "" && creds.Password != "":
+		return creds, nil
+	default:
+		return cfp.Credentials{}, fmt.Errorf("secret '%s/%s' must hold either a '%s', or a '%s' and a '%s'",
+			secret.Namespace, secret.Name, TokenKey, UsernameKey, PasswordKey)
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager, watching only
+// the credentials Secret.
+func (r *CredentialsReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("credentials").
+		For(&corev1.Secret{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			return client.ObjectKeyFromObject(obj) == r.SecretRef
+		}))).
+		Complete(r)
+}
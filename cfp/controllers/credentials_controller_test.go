@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/internal/cfp"
+)
+
+func Test_CredentialsReconciler(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	ns, err := testEnv.CreateNamespace(ctx, "credentials-ns")
+	g.Expect(err).NotTo(HaveOccurred())
+	defer func() {
+		g.Expect(testEnv.Delete(ctx, ns)).To(Succeed())
+	}()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cfp-api-credentials",
+			Namespace: ns.Name,
+		},
+		Data: map[string][]byte{TokenKey: []byte("secret-token")},
+	}
+	g.Expect(testEnv.Create(ctx, secret)).To(Succeed())
+
+	recorder := record.NewFakeRecorder(10)
+	r := &CredentialsReconciler{
+		Client:        testEnv.Client,
+		EventRecorder: recorder,
+		Store:         &cfp.CredentialStore{},
+		SecretRef:     client.ObjectKeyFromObject(secret),
+	}
+	req := ctrl.Request{NamespacedName: r.SecretRef}
+
+	_, err = r.Reconcile(ctx, req)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(r.Store.Get()).To(Equal(cfp.Credentials{Token: "secret-token"}))
+
+	// Invalid credentials do not replace the previous ones, and are reported
+	// by a warning event on the Secret
+	secret.Data = map[string][]byte{UsernameKey: []byte("user")}
+	g.Expect(testEnv.Update(ctx, secret)).To(Succeed())
+	_, err = r.Reconcile(ctx, req)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(r.Store.Get()).To(Equal(cfp.Credentials{Token: "secret-token"}))
+	g.Expect(recorder.Events).To(Receive(And(
+		HavePrefix(corev1.EventTypeWarning+" "+invalidCredentialsReason),
+		ContainSubstring("must hold either a 'token'"),
+	)))
+
+	// Rotated credentials replace the previous ones
+	secret.Data = map[string][]byte{UsernameKey: []byte("user"), PasswordKey: []byte("pass")}
+	g.Expect(testEnv.Update(ctx, secret)).To(Succeed())
+	_, err = r.Reconcile(ctx, req)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(r.Store.Get()).To(Equal(cfp.Credentials{Username: "user", Password: "pass"}))
+
+	// Deleted credentials are no longer sent
+	g.Expect(testEnv.Delete(ctx, secret)).To(Succeed())
+	_, err = r.Reconcile(ctx, req)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(r.Store.Get()).To(Equal(cfp.Credentials{}))
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// driftCorrections counts the fields of the CFP API records which were
+// changed outside of the cluster and corrected by the controllers.
+var driftCorrections = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "cfp_drift_corrections_total",
+		Help: "Number of fields of CFP API records corrected after drifting from their object.",
+	},
+	[]string{"kind", "field"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(driftCorrections)
+}
+
+// driftedFields returns the JSON names of the fields which differ between
+// two values of the same struct type.
+func driftedFields(want, got interface{}) []string {
+	wantValue, gotValue := reflect.ValueOf(want), reflect.ValueOf(got)
+
+	var fields []string
+	for i := 0; i < wantValue.NumField(); i++ {
+		if equality.Semantic.DeepEqual(wantValue.Field(i).Interface(), gotValue.Field(i).Interface()) {
+			continue
+		}
+		name := strings.Split(wantValue.Type().Field(i).Tag.Get("json"), ",")[0]
+		if name == "" {
+			name = wantValue.Type().Field(i).Name
+		}
+		fields = append(fields, name)
+	}
+	return fields
+}
+
+// recordDrift counts the corrected fields of a record of the given kind.
+func recordDrift(kind string, fields []string) {
+	for _, field := range fields {
+		driftCorrections.WithLabelValues(kind, field).Inc()
+	}
+}
@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_DriftedFields(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	testCases := []struct {
+		name    string
+		want    proposalFields
+		got     proposalFields
+		drifted []string
+	}{
+		{
+			name: "test no drift",
+			want: proposalFields{Title: "title", SpeakerIDs: []string{"ns-speaker"}},
+			got:  proposalFields{Title: "title", SpeakerIDs: []string{"ns-speaker"}},
+		},
+		{
+			name:    "test drifted fields are named after their JSON key",
+			want:    proposalFields{Title: "title", Abstract: "abstract", Final: true},
+			got:     proposalFields{Title: "changed", Abstract: "abstract"},
+			drifted: []string{"title", "final"},
+		},
+		{
+			name:    "test drifted list",
+			want:    proposalFields{SpeakerIDs: []string{"ns-speaker", "ns-co-speaker"}},
+			got:     proposalFields{SpeakerIDs: []string{"ns-speaker"}},
+			drifted: []string{"speakerIDs"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g.Expect(driftedFields(tc.want, tc.got)).To(Equal(tc.drifted))
+		})
+	}
+}
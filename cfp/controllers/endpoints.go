@@ -0,0 +1,87 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	talksv1 "github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/api/v1"
+)
+
+// cfpEndpoint returns the address of the CFP API of the conference, which
+// defaults to defaultEndpoint when there is no conference or it does not set
+// any. The endpoint of a conference is only used if it is one of the allowed
+// endpoints, so that tenants can not have the controller send requests to
+// an address of their choice.
+func cfpEndpoint(conference *talksv1.Conference, defaultEndpoint string, allowed []string) (string, error) {
+	if conference == nil || conference.Spec.Endpoint == "" {
+		return defaultEndpoint, nil
+	}
+
+	if endpoint, ok := allowedEndpoint(conference.Spec.Endpoint, defaultEndpoint, allowed); ok {
+		return endpoint, nil
+	}
+	return "", &stallingError{
+		Reason: talksv1.EndpointNotAllowedReason,
+		Err: fmt.Errorf("the endpoint '%s' of conference '%s' is not an allowed CFP API endpoint",
+			conference.Spec.Endpoint, conference.Name),
+	}
+}
+
+// recordedEndpoint returns the address of the CFP API recorded in the status
+// of an object as holding its record, from which the record is deleted. The
+// recorded endpoint is only used while it is still allowed.
+func recordedEndpoint(recorded, defaultEndpoint string, allowed []string) (string, error) {
+	if endpoint, ok := allowedEndpoint(recorded, defaultEndpoint, allowed); ok {
+		return endpoint, nil
+	}
+	return "", &stallingError{
+		Reason: talksv1.EndpointNotAllowedReason,
+		Err:    fmt.Errorf("the endpoint '%s' holding the record is no longer an allowed CFP API endpoint", recorded),
+	}
+}
+
+// allowedEndpoint returns the default endpoint or the allowed endpoint that is
+// the same endpoint as the given one, and false if there is none.
+func allowedEndpoint(endpoint, defaultEndpoint string, allowed []string) (string, bool) {
+	if sameEndpoint(endpoint, defaultEndpoint) {
+		return defaultEndpoint, true
+	}
+	for _, e := range allowed {
+		if sameEndpoint(endpoint, e) {
+			return e, true
+		}
+	}
+	return "", false
+}
+
+// speakerEndpoint returns the address of the CFP API holding the record of
+// the speaker, the records created before it was recorded in the status are
+// held by the CFP API of the controller.
+func speakerEndpoint(speaker *talksv1.Speaker, defaultEndpoint string) string {
+	if speaker.Status.Endpoint == "" {
+		return defaultEndpoint
+	}
+	return speaker.Status.Endpoint
+}
+
+// sameEndpoint returns true if both addresses are the same endpoint,
+// ignoring a trailing slash.
+func sameEndpoint(a, b string) bool {
+	return strings.TrimSuffix(a, "/") == strings.TrimSuffix(b, "/")
+}
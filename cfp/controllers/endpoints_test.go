@@ -0,0 +1,162 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	talksv1 "github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/api/v1"
+	talksv2 "github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/api/v2"
+)
+
+func Test_cfpEndpoint(t *testing.T) {
+	const defaultEndpoint = "http://cfp-api:50001"
+	allowed := []string{"https://cfp.kubecon.eu"}
+
+	conference := func(endpoint string) *talksv1.Conference {
+		return &talksv1.Conference{
+			ObjectMeta: metav1.ObjectMeta{Name: "kubecon"},
+			Spec:       talksv1.ConferenceSpec{Endpoint: endpoint},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		conference *talksv1.Conference
+		want       string
+		wantErr    bool
+	}{
+		{
+			name: "no conference uses the default endpoint",
+			want: defaultEndpoint,
+		},
+		{
+			name:       "conference without endpoint uses the default endpoint",
+			conference: conference(""),
+			want:       defaultEndpoint,
+		},
+		{
+			name:       "conference with the default endpoint",
+			conference: conference(defaultEndpoint + "/"),
+			want:       defaultEndpoint,
+		},
+		{
+			name:       "conference with an allowed endpoint",
+			conference: conference("https://cfp.kubecon.eu/"),
+			want:       "https://cfp.kubecon.eu",
+		},
+		{
+			name:       "conference with an endpoint which is not allowed",
+			conference: conference("http://attacker.example.com"),
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := cfpEndpoint(tt.conference, defaultEndpoint, allowed)
+			if tt.wantErr {
+				g.Expect(err).To(MatchError(ContainSubstring("is not an allowed CFP API endpoint")))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_speakerEndpoint(t *testing.T) {
+	g := NewWithT(t)
+
+	speaker := &talksv1.Speaker{}
+	g.Expect(speakerEndpoint(speaker, "http://cfp-api:50001")).To(Equal("http://cfp-api:50001"))
+
+	speaker.Status.Endpoint = "https://cfp.kubecon.eu"
+	g.Expect(speakerEndpoint(speaker, "http://cfp-api:50001")).To(Equal("https://cfp.kubecon.eu"))
+}
+
+func Test_recordedEndpoint(t *testing.T) {
+	g := NewWithT(t)
+
+	const defaultEndpoint = "http://cfp-api:50001"
+	allowed := []string{"https://cfp.kubecon.eu"}
+
+	got, err := recordedEndpoint(defaultEndpoint, defaultEndpoint, allowed)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(Equal(defaultEndpoint))
+
+	got, err = recordedEndpoint("https://cfp.kubecon.eu/", defaultEndpoint, allowed)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(Equal("https://cfp.kubecon.eu"))
+
+	_, err = recordedEndpoint("https://cfp.kubecon.io", defaultEndpoint, allowed)
+	g.Expect(err).To(MatchError(ContainSubstring("is no longer an allowed CFP API endpoint")))
+}
+
+func Test_resolveEndpoint_deletion(t *testing.T) {
+	const defaultEndpoint = "http://cfp-api:50001"
+	allowed := []string{"https://cfp.kubecon.eu"}
+	now := metav1.Now()
+	deleted := metav1.ObjectMeta{Name: "deleted", DeletionTimestamp: &now}
+
+	proposals := &ProposalReconciler{CfpAPI: defaultEndpoint, AllowedEndpoints: allowed}
+	speakers := &SpeakerReconciler{CfpAPI: defaultEndpoint, AllowedEndpoints: allowed}
+	reviews := &ReviewReconciler{CfpAPI: defaultEndpoint, AllowedEndpoints: allowed}
+
+	tests := []struct {
+		name    string
+		resolve func(endpoint string) (string, error)
+	}{
+		{
+			name: "proposal",
+			resolve: func(endpoint string) (string, error) {
+				obj := &talksv2.Proposal{ObjectMeta: deleted}
+				obj.Status.Submission = talksv1.ProposalStateDraft
+				obj.Status.Endpoint = endpoint
+				_, got, err := proposals.resolveEndpoint(context.TODO(), obj)
+				return got, err
+			},
+		},
+		{
+			name: "speaker",
+			resolve: func(endpoint string) (string, error) {
+				obj := &talksv1.Speaker{ObjectMeta: deleted}
+				obj.Status.ID = "ns-deleted"
+				obj.Status.Endpoint = endpoint
+				_, got, err := speakers.resolveEndpoint(context.TODO(), obj)
+				return got, err
+			},
+		},
+		{
+			name: "review",
+			resolve: func(endpoint string) (string, error) {
+				obj := &talksv1.Review{ObjectMeta: deleted}
+				obj.Status.ID = "ns-deleted"
+				obj.Status.Endpoint = endpoint
+				_, got, err := reviews.resolveEndpoint(context.TODO(), obj)
+				return got, err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			// The record is deleted from the recorded endpoint, without
+			// getting the conference
+			got, err := tt.resolve("https://cfp.kubecon.eu/")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal("https://cfp.kubecon.eu"))
+
+			// An endpoint which is no longer allowed stalls the deletion
+			_, err = tt.resolve("https://cfp.kubecon.io")
+			var stallErr *stallingError
+			g.Expect(errors.As(err, &stallErr)).To(BeTrue())
+			g.Expect(stallErr.Reason).To(Equal(talksv1.EndpointNotAllowedReason))
+		})
+	}
+}
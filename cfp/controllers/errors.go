@@ -0,0 +1,163 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"errors"
+	"math/rand"
+	"time"
+
+	aclapi "github.com/fluxcd/pkg/apis/acl"
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	"k8s.io/client-go/util/workqueue"
+
+	talksv1 "github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/api/v1"
+	"github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/internal/cfp"
+)
+
+// defaultThrottleDelay is the delay after which a throttled request is
+// retried when the CFP API does not request one.
+const defaultThrottleDelay = 30 * time.Second
+
+// stallingError is returned by a reconciliation which can not succeed until
+// the object is changed. The object is marked as Stalled and is not requeued.
+type stallingError struct {
+	// Reason is the reason of the Stalled and Ready conditions.
+	Reason string
+	// Err is the error that caused the stall.
+	Err error
+}
+
+func (e *stallingError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *stallingError) Unwrap() error {
+	return e.Err
+}
+
+// referenceStallReasons are the reasons of the stalls caused by the objects
+// an object references or by the configuration of the controller, which can
+// be lifted without changing the object itself.
+var referenceStallReasons = []string{
+	talksv1.EndpointNotAllowedReason,
+	talksv1.EndpointMismatchReason,
+	talksv1.TalkTypeNotAcceptedReason,
+	talksv1.CFPClosedReason,
+	talksv1.SpeakerDeletedReason,
+	aclapi.AccessDeniedReason,
+}
+
+// skipStalled returns true if the object is stalled on its observed
+// generation for a reason only a change of the object can lift, in which case
+// reconciling it again would fail the same way. The deletion of a stalled
+// object is always reconciled.
+func skipStalled(obj conditions.Getter, observedGeneration int64) bool {
+	if !obj.GetDeletionTimestamp().IsZero() || obj.GetGeneration() != observedGeneration || !conditions.IsStalled(obj) {
+		return false
+	}
+	reason := conditions.GetReason(obj, meta.StalledCondition)
+	for _, r := range referenceStallReasons {
+		if reason == r {
+			return false
+		}
+	}
+	return true
+}
+
+// waitingError is returned by a reconciliation which has to wait for an
+// external event before it can proceed, e.g. for the call for papers to open.
+// The object is not Ready and is requeued after RequeueAfter.
+type waitingError struct {
+	// Reason is the reason of the Ready condition.
+	Reason string
+	// RequeueAfter is the time after which the object is reconciled again.
+	RequeueAfter time.Duration
+	// Err is the reason to wait.
+	Err error
+}
+
+func (e *waitingError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *waitingError) Unwrap() error {
+	return e.Err
+}
+
+// classifyError returns the error of a reconciliation with the errors of the
+// CFP API which can not succeed by retrying converted to a stallingError, and
+// the throttling ones to a waitingError honouring the requested delay.
+// Other errors are transient, and retried with the backoff of the controller.
+func classifyError(err error) error {
+	var (
+		stallErr *stallingError
+		waitErr  *waitingError
+		apiErr   *cfp.Error
+	)
+	if errors.As(err, &stallErr) || errors.As(err, &waitErr) || !errors.As(err, &apiErr) {
+		return err
+	}
+
+	if cfp.IsTerminal(err) {
+		return &stallingError{Reason: apiErrorReason(apiErr), Err: err}
+	}
+	if delay, ok := cfp.IsThrottled(err); ok {
+		if delay <= 0 {
+			delay = defaultThrottleDelay
+		}
+		return &waitingError{Reason: talksv1.ThrottledReason, RequeueAfter: delay, Err: err}
+	}
+	return err
+}
+
+// apiErrorReason returns the condition reason of an error of the CFP API,
+// which is the reason of the operation that failed unless the API rejected the
+// fields of the record or did not find it.
+func apiErrorReason(apiErr *cfp.Error) string {
+	switch {
+	case cfp.IsValidation(apiErr):
+		return talksv1.ValidationFailedReason
+	case cfp.IsNotFound(apiErr):
+		return talksv1.RecordNotFoundReason
+	default:
+		return apiErr.Reason.Reason
+	}
+}
+
+// jitteredRateLimiter adds a random jitter of up to maxFactor to the
+// exponential backoff of the failing items, so that the objects failing
+// together on a transient error are not all retried at once.
+type jitteredRateLimiter struct {
+	workqueue.RateLimiter
+	maxFactor float64
+}
+
+// newJitteredRateLimiter returns the rate limiter with a jitter of up to half
+// of the backoff.
+func newJitteredRateLimiter(limiter workqueue.RateLimiter) workqueue.RateLimiter {
+	return &jitteredRateLimiter{
+		RateLimiter: limiter,
+		maxFactor:   0.5,
+	}
+}
+
+func (r *jitteredRateLimiter) When(item interface{}) time.Duration {
+	d := r.RateLimiter.When(item)
+	return d + time.Duration(rand.Float64()*r.maxFactor*float64(d))
+}
@@ -0,0 +1,137 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fluxcd/pkg/runtime/conditions"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	talksv1 "github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/api/v1"
+	"github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/internal/cfp"
+)
+
+func Test_ClassifyError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStall  bool
+		wantReason string
+		wantWait   time.Duration
+	}{
+		{
+			name:       "rejected request stalls",
+			err:        &cfp.Error{Reason: cfp.ErrCreateSpeaker, StatusCode: http.StatusBadRequest, Err: errors.New("invalid email")},
+			wantStall:  true,
+			wantReason: cfp.ErrCreateSpeaker.Reason,
+		},
+		{
+			name:       "rejected fields stall with the validation reason",
+			err:        &cfp.Error{Reason: cfp.ErrUpdateProposal, StatusCode: http.StatusBadRequest, Code: "ValidationFailed", Err: errors.New("title must be specified")},
+			wantStall:  true,
+			wantReason: talksv1.ValidationFailedReason,
+		},
+		{
+			name:     "throttled request waits for the requested delay",
+			err:      &cfp.Error{Reason: cfp.ErrUpdateSpeaker, StatusCode: http.StatusTooManyRequests, RetryAfter: 2 * time.Minute, Err: errors.New("too many requests")},
+			wantWait: 2 * time.Minute,
+		},
+		{
+			name:     "unavailable API waits for the default delay",
+			err:      fmt.Errorf("wrapped: %w", &cfp.Error{Reason: cfp.ErrFetchProposal, StatusCode: http.StatusServiceUnavailable, Err: errors.New("unavailable")}),
+			wantWait: defaultThrottleDelay,
+		},
+		{
+			name: "server error is transient",
+			err:  &cfp.Error{Reason: cfp.ErrFetchProposal, StatusCode: http.StatusInternalServerError, Err: errors.New("internal error")},
+		},
+		{
+			name: "modified record is transient",
+			err:  &cfp.Error{Reason: cfp.ErrUpdateSpeaker, StatusCode: http.StatusPreconditionFailed, Err: errors.New("modified")},
+		},
+		{
+			name: "not found is transient",
+			err:  &cfp.Error{Reason: cfp.ErrFetchProposal, StatusCode: http.StatusNotFound, Err: errors.New("not found")},
+		},
+		{
+			name: "other errors are transient",
+			err:  errors.New("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := classifyError(tt.err)
+			g.Expect(errors.Is(err, tt.err)).To(BeTrue())
+
+			var stallErr *stallingError
+			g.Expect(errors.As(err, &stallErr)).To(Equal(tt.wantStall))
+			if tt.wantStall {
+				g.Expect(stallErr.Reason).To(Equal(tt.wantReason))
+			}
+
+			var waitErr *waitingError
+			g.Expect(errors.As(err, &waitErr)).To(Equal(tt.wantWait > 0))
+			if tt.wantWait > 0 {
+				g.Expect(waitErr.Reason).To(Equal(talksv1.ThrottledReason))
+				g.Expect(waitErr.RequeueAfter).To(Equal(tt.wantWait))
+			}
+		})
+	}
+}
+
+func Test_skipStalled(t *testing.T) {
+	stalled := func(reason string, generation, observedGeneration int64) *talksv1.Speaker {
+		obj := &talksv1.Speaker{ObjectMeta: metav1.ObjectMeta{Generation: generation}}
+		obj.Status.ObservedGeneration = observedGeneration
+		if reason != "" {
+			conditions.MarkStalled(obj, reason, "stalled")
+		}
+		return obj
+	}
+
+	tests := []struct {
+		name string
+		obj  *talksv1.Speaker
+		want bool
+	}{
+		{
+			name: "object which is not stalled is reconciled",
+			obj:  stalled("", 1, 1),
+		},
+		{
+			name: "object stalled on its generation is skipped",
+			obj:  stalled(talksv1.AlreadyExistsReason, 1, 1),
+			want: true,
+		},
+		{
+			name: "object stalled on a previous generation is reconciled",
+			obj:  stalled(talksv1.AlreadyExistsReason, 2, 1),
+		},
+		{
+			name: "object stalled by a referenced object is reconciled",
+			obj:  stalled(talksv1.EndpointNotAllowedReason, 1, 1),
+		},
+		{
+			name: "deleted stalled object is reconciled",
+			obj: func() *talksv1.Speaker {
+				obj := stalled(talksv1.AlreadyExistsReason, 1, 1)
+				now := metav1.Now()
+				obj.DeletionTimestamp = &now
+				return obj
+			}(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(skipStalled(tt.obj, tt.obj.Status.ObservedGeneration)).To(Equal(tt.want))
+		})
+	}
+}
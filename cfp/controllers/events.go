@@ -0,0 +1,134 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/pkg/apis/meta"
+
+	"github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/internal/cfp"
+)
+
+// Reasons of the events recorded for the operations made on the CFP API.
+const (
+	speakerCreatedReason    = "SpeakerCreated"
+	speakerUpdatedReason    = "SpeakerUpdated"
+	speakerDeletedReason    = "SpeakerDeleted"
+	speakerAdoptedReason    = "SpeakerAdopted"
+	speakerRecreatedReason  = "SpeakerRecreated"
+	proposalCreatedReason   = "ProposalCreated"
+	proposalUpdatedReason   = "ProposalUpdated"
+	proposalFinalizedReason = "ProposalFinalized"
+	proposalDeletedReason   = "ProposalDeleted"
+	proposalWithdrawnReason = "ProposalWithdrawn"
+	proposalAdoptedReason   = "ProposalAdopted"
+	proposalRecreatedReason = "ProposalRecreated"
+	reviewCreatedReason     = "ReviewCreated"
+	reviewUpdatedReason     = "ReviewUpdated"
+	reviewDeletedReason     = "ReviewDeleted"
+
+	invalidCredentialsReason = "InvalidCredentials"
+)
+
+// dedupCacheSize is the number of recorded events a DedupEventRecorder
+// remembers, the least recently recorded ones are forgotten first.
+const dedupCacheSize = 4096
+
+// DedupEventRecorder is a record.EventRecorder which drops the events
+// identical to an event recorded for the same object less than Window ago, so
+// that requeue loops do not flood the event stream.
+type DedupEventRecorder struct {
+	record.EventRecorder
+	Window time.Duration
+
+	mu       sync.Mutex
+	recorded *cache.LRUExpireCache
+}
+
+// NewDedupEventRecorder returns a DedupEventRecorder recording the events
+// with recorder.
+func NewDedupEventRecorder(recorder record.EventRecorder, window time.Duration) *DedupEventRecorder {
+	return &DedupEventRecorder{
+		EventRecorder: recorder,
+		Window:        window,
+		recorded:      cache.NewLRUExpireCache(dedupCacheSize),
+	}
+}
+
+// Event records the event unless it is a duplicate.
+func (r *DedupEventRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	if r.isDuplicate(object, eventtype, reason, message) {
+		return
+	}
+	r.EventRecorder.Event(object, eventtype, reason, message)
+}
+
+// Eventf records the event unless it is a duplicate.
+func (r *DedupEventRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// AnnotatedEventf records the event unless it is a duplicate.
+func (r *DedupEventRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	message := fmt.Sprintf(messageFmt, args...)
+	if r.isDuplicate(object, eventtype, reason, message) {
+		return
+	}
+	r.EventRecorder.AnnotatedEventf(object, annotations, eventtype, reason, "%s", message)
+}
+
+// isDuplicate returns true if the event has already been recorded for the
+// object less than Window ago, and remembers it otherwise.
+func (r *DedupEventRecorder) isDuplicate(object runtime.Object, eventtype, reason, message string) bool {
+	key := fmt.Sprintf("%s/%s/%s/%s", eventtype, reason, message, objectUID(object))
+	now := time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// The events recorded outside of the window expire from the cache
+	if t, ok := r.recorded.Get(key); ok && now.Sub(t.(time.Time)) < r.Window {
+		return true
+	}
+	r.recorded.Add(key, now, r.Window)
+	return false
+}
+
+// failureReason returns the reason of an event recording err.
+func failureReason(err error) string {
+	var apiErr *cfp.Error
+	if errors.As(err, &apiErr) {
+		return apiErrorReason(apiErr)
+	}
+	return meta.FailedReason
+}
+
+func objectUID(object runtime.Object) string {
+	if o, ok := object.(client.Object); ok {
+		return string(o.GetUID())
+	}
+	return fmt.Sprintf("%p", object)
+}
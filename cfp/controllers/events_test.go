@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	talksv1 "github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/api/v1"
+)
+
+func Test_DedupEventRecorder(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	speaker := &talksv1.Speaker{ObjectMeta: metav1.ObjectMeta{Name: "speaker", UID: "speaker-uid"}}
+	other := &talksv1.Speaker{ObjectMeta: metav1.ObjectMeta{Name: "other", UID: "other-uid"}}
+
+	fake := record.NewFakeRecorder(10)
+	recorder := NewDedupEventRecorder(fake, time.Minute)
+
+	recorder.Event(speaker, corev1.EventTypeWarning, "CreateFailed", "failed")
+	recorder.Eventf(speaker, corev1.EventTypeWarning, "CreateFailed", "%s", "failed")
+	recorder.Event(speaker, corev1.EventTypeWarning, "CreateFailed", "failed again")
+	recorder.Event(other, corev1.EventTypeWarning, "CreateFailed", "failed")
+	g.Expect(fake.Events).To(HaveLen(3))
+
+	// Events are recorded again once the window has passed
+	recorder.Window = 0
+	recorder.Event(speaker, corev1.EventTypeWarning, "CreateFailed", "failed")
+	g.Expect(fake.Events).To(HaveLen(4))
+	g.Expect(<-fake.Events).To(Equal("Warning CreateFailed failed"))
+}
+
+func Test_DedupEventRecorder_Expiry(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	fake := record.NewFakeRecorder(dedupCacheSize + 10)
+	recorder := NewDedupEventRecorder(fake, 50*time.Millisecond)
+
+	speaker := &talksv1.Speaker{ObjectMeta: metav1.ObjectMeta{Name: "speaker", UID: "speaker-uid"}}
+	recorder.Event(speaker, corev1.EventTypeWarning, "CreateFailed", "failed")
+	recorder.Event(speaker, corev1.EventTypeWarning, "CreateFailed", "failed")
+	g.Expect(fake.Events).To(HaveLen(1))
+
+	// The event expires once the window has passed
+	time.Sleep(100 * time.Millisecond)
+	recorder.Event(speaker, corev1.EventTypeWarning, "CreateFailed", "failed")
+	g.Expect(fake.Events).To(HaveLen(2))
+
+	// The number of remembered events is bounded
+	recorder.Window = time.Minute
+	for i := 0; i < dedupCacheSize+1; i++ {
+		recorder.Event(speaker, corev1.EventTypeNormal, "Reconciled", fmt.Sprintf("event %d", i))
+	}
+	g.Expect(recorder.recorded.Keys()).To(HaveLen(dedupCacheSize))
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+)
+
+const (
+	defaultMaxConcurrentReconciles = 1
+	defaultMinRetryDelay           = 5 * time.Millisecond
+	defaultMaxRetryDelay           = 1000 * time.Second
+	defaultQueueQPS                = 10
+	defaultQueueBurst              = 100
+)
+
+// ReconcilerOptions tunes the workers and the work queue of a controller.
+// The zero value of a field stands for its default.
+type ReconcilerOptions struct {
+	// MaxConcurrentReconciles is the number of objects reconciled at once.
+	MaxConcurrentReconciles int
+
+	// MinRetryDelay and MaxRetryDelay bound the exponential backoff of the
+	// objects failing to reconcile.
+	MinRetryDelay time.Duration
+	MaxRetryDelay time.Duration
+
+	// QueueQPS and QueueBurst limit the rate at which all the objects of the
+	// controller are retried.
+	QueueQPS   float64
+	QueueBurst int
+}
+
+// BindFlags binds the options of the named controller to flags prefixed with
+// its name, e.g. --speaker-concurrent.
+func (o *ReconcilerOptions) BindFlags(fs *flag.FlagSet, name string) {
+	fs.IntVar(&o.MaxConcurrentReconciles, fmt.Sprintf("%s-concurrent", name), defaultMaxConcurrentReconciles,
+		fmt.Sprintf("The number of %s objects reconciled at once.", name))
+	fs.DurationVar(&o.MinRetryDelay, fmt.Sprintf("%s-min-retry-delay", name), defaultMinRetryDelay,
+		fmt.Sprintf("The delay after which a %s object failing to reconcile is first retried.", name))
+	fs.DurationVar(&o.MaxRetryDelay, fmt.Sprintf("%s-max-retry-delay", name), defaultMaxRetryDelay,
+		fmt.Sprintf("The maximum delay after which a %s object failing to reconcile is retried.", name))
+	fs.Float64Var(&o.QueueQPS, fmt.Sprintf("%s-queue-qps", name), defaultQueueQPS,
+		fmt.Sprintf("The number of %s objects retried per second.", name))
+	fs.IntVar(&o.QueueBurst, fmt.Sprintf("%s-queue-burst", name), defaultQueueBurst,
+		fmt.Sprintf("The number of %s objects retried at once.", name))
+}
+
+// controllerOptions returns the controller options of o, with the defaults
+// set for its zero fields.
+func (o ReconcilerOptions) controllerOptions() controller.Options {
+	if o.MaxConcurrentReconciles <= 0 {
+		o.MaxConcurrentReconciles = defaultMaxConcurrentReconciles
+	}
+	if o.MinRetryDelay <= 0 {
+		o.MinRetryDelay = defaultMinRetryDelay
+	}
+	if o.MaxRetryDelay <= 0 {
+		o.MaxRetryDelay = defaultMaxRetryDelay
+	}
+	if o.QueueQPS <= 0 {
+		o.QueueQPS = defaultQueueQPS
+	}
+	if o.QueueBurst <= 0 {
+		o.QueueBurst = defaultQueueBurst
+	}
+
+	return controller.Options{
+		MaxConcurrentReconciles: o.MaxConcurrentReconciles,
+		RateLimiter: newJitteredRateLimiter(workqueue.NewMaxOfRateLimiter(
+			workqueue.NewItemExponentialFailureRateLimiter(o.MinRetryDelay, o.MaxRetryDelay),
+			&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(o.QueueQPS), o.QueueBurst)},
+		)),
+	}
+}
@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_ReconcilerOptions(t *testing.T) {
+	g := NewWithT(t)
+
+	var opts ReconcilerOptions
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	opts.BindFlags(fs, "proposal")
+	g.Expect(fs.Parse([]string{"--proposal-concurrent=10", "--proposal-min-retry-delay=1s"})).To(Succeed())
+	g.Expect(opts.MaxConcurrentReconciles).To(Equal(10))
+	g.Expect(opts.MinRetryDelay).To(Equal(time.Second))
+	g.Expect(opts.MaxRetryDelay).To(Equal(defaultMaxRetryDelay))
+
+	ctrlOpts := opts.controllerOptions()
+	g.Expect(ctrlOpts.MaxConcurrentReconciles).To(Equal(10))
+	// The first retry waits for the min delay, with up to half of it as jitter
+	g.Expect(ctrlOpts.RateLimiter.When("item")).To(BeNumerically("~", 1250*time.Millisecond, 250*time.Millisecond))
+
+	// The zero value stands for the defaults
+	g.Expect(ReconcilerOptions{}.controllerOptions().MaxConcurrentReconciles).To(Equal(defaultMaxConcurrentReconciles))
+}
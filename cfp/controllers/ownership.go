@@ -0,0 +1,43 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ownerMarker returns the marker recorded on the CFP API record of an object,
+// made of the ID of the cluster and the UID of the object. It identifies the
+// records created by the object, e.g. when the status holding their ID could
+// not be patched after they were created.
+func ownerMarker(clusterID string, obj client.Object) string {
+	return fmt.Sprintf("%s/%s", clusterID, obj.GetUID())
+}
+
+// ClusterID returns the UID of the kube-system namespace, which identifies
+// the cluster in the owner markers of the CFP API records.
+func ClusterID(ctx context.Context, c client.Reader) (string, error) {
+	var ns corev1.Namespace
+	if err := c.Get(ctx, client.ObjectKey{Name: "kube-system"}, &ns); err != nil {
+		return "", fmt.Errorf("unable to get the kube-system namespace: %w", err)
+	}
+	return string(ns.UID), nil
+}
@@ -17,31 +17,39 @@ limitations under the License.
 package controllers
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	aclapi "github.com/fluxcd/pkg/apis/acl"
 	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/acl"
 	"github.com/fluxcd/pkg/runtime/conditions"
 	"github.com/fluxcd/pkg/runtime/patch"
+	cfptypes "github.com/scottrigby/cfp-api/pkg/types"
 	talksv1 "github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/api/v1"
+	talksv2 "github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/api/v2"
 	"github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/internal/cfp"
+	"go.opentelemetry.io/otel/attribute"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -53,39 +61,157 @@ var proposalOwnedConditions = []string{
 	talksv1.CreateFailedCondition,
 	talksv1.UpdateFailedCondition,
 	talksv1.FetchFailedCondition,
+	talksv1.ReviewedCondition,
+	talksv1.DriftedCondition,
 }
 
 // ProposalReconciler reconciles a Proposal object
 type ProposalReconciler struct {
 	client.Client
 	HTTPClient     *http.Client
+	EventRecorder  record.EventRecorder
 	ControllerName string
 	CfpAPI         string
+
+	// AllowedEndpoints are the addresses of the CFP APIs other than CfpAPI
+	// that Conferences can collect the proposals of.
+	AllowedEndpoints []string
+
+	// RateLimiter limits the requests sent to the CFP API, it is shared by
+	// the reconcilers.
+	RateLimiter *cfp.RateLimiter
+
+	// Credentials authenticate the requests sent to the CFP API, if set.
+	Credentials *cfp.CredentialStore
+
+	// DefaultInterval is the interval at which Proposals without an interval
+	// are compared with their record in the CFP API.
+	DefaultInterval time.Duration
+
+	// ClusterID identifies the cluster in the owner marker of the records
+	// created in the CFP API.
+	ClusterID string
+
+	// MinReviews is the number of reviews a final proposal needs before the
+	// program committee can make a decision on it.
+	MinReviews int
+
+	// NoCrossNamespaceRefs denies the Proposals access to the Speakers of
+	// other namespaces, regardless of their ACL.
+	NoCrossNamespaceRefs bool
 }
 
-// SetupWithManager sets up the controller with the Manager.
+// SetupWithManager sets up the controller with the Manager, with the default
+// options.
 func (r *ProposalReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	if err := mgr.GetCache().IndexField(context.TODO(), &talksv1.Proposal{}, talksv1.SpeakerIndexKey,
-		r.indexProposalBySpeakerName); err != nil {
+	return r.SetupWithManagerAndOptions(mgr, ReconcilerOptions{})
+}
+
+// SetupWithManagerAndOptions sets up the controller with the Manager and the
+// given options.
+func (r *ProposalReconciler) SetupWithManagerAndOptions(mgr ctrl.Manager, opts ReconcilerOptions) error {
+	if err := mgr.GetCache().IndexField(context.TODO(), &talksv2.Proposal{}, talksv1.SpeakerIndexKey,
+		r.indexProposalBySpeaker); err != nil {
+		return fmt.Errorf("failed setting index fields: %w", err)
+	}
+
+	if err := mgr.GetCache().IndexField(context.TODO(), &talksv2.Proposal{}, talksv1.ConferenceIndexKey,
+		r.indexProposalByConference); err != nil {
+		return fmt.Errorf("failed setting index fields: %w", err)
+	}
+
+	if err := mgr.GetCache().IndexField(context.TODO(), &talksv1.Review{}, talksv1.ProposalIndexKey,
+		r.indexReviewByProposal); err != nil {
 		return fmt.Errorf("failed setting index fields: %w", err)
 	}
 
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&talksv1.Proposal{}).
+		For(&talksv2.Proposal{}).
+		WithOptions(opts.controllerOptions()).
 		Watches(
 			&source.Kind{Type: &talksv1.Speaker{}},
 			handler.EnqueueRequestsFromMapFunc(r.requestsForSpeakerChange),
 			builder.WithPredicates(SpeakerChangePredicate{}),
 		).
+		Watches(
+			&source.Kind{Type: &talksv1.Conference{}},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForConferenceChange),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		Watches(
+			&source.Kind{Type: &talksv1.Review{}},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForReviewChange),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
 		Complete(r)
 }
 
-func (r *ProposalReconciler) indexProposalBySpeakerName(o client.Object) []string {
-	p, ok := o.(*talksv1.Proposal)
+// indexProposalBySpeaker indexes proposals by the namespace/name keys of
+// the speakers they reference.
+func (r *ProposalReconciler) indexProposalBySpeaker(o client.Object) []string {
+	p, ok := o.(*talksv2.Proposal)
 	if !ok {
 		panic(fmt.Sprintf("Expected a Proposal, got %T", o))
 	}
-	return []string{p.Spec.SpeakerRef.Name}
+
+	var keys []string
+	for _, ref := range p.Spec.SpeakerRefs {
+		keys = append(keys, speakerKey(p, ref).String())
+	}
+	return keys
+}
+
+// indexProposalByConference indexes proposals by the namespace/name key of
+// the conference they are submitted to.
+func (r *ProposalReconciler) indexProposalByConference(o client.Object) []string {
+	p, ok := o.(*talksv2.Proposal)
+	if !ok {
+		panic(fmt.Sprintf("Expected a Proposal, got %T", o))
+	}
+
+	if p.Spec.ConferenceRef == nil {
+		return nil
+	}
+	return []string{conferenceKey(p).String()}
+}
+
+func (r *ProposalReconciler) requestsForConferenceChange(o client.Object) []reconcile.Request {
+	conference, ok := o.(*talksv1.Conference)
+	if !ok {
+		panic(fmt.Sprintf("Expected a Conference, got %T", o))
+	}
+
+	ctx := context.Background()
+	var list talksv2.ProposalList
+	if err := r.List(ctx, &list, client.MatchingFields{talksv1.ConferenceIndexKey: client.ObjectKeyFromObject(conference).String()}); err != nil {
+		return nil
+	}
+
+	var reqs []reconcile.Request
+	for _, i := range list.Items {
+		reqs = append(reqs, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&i)})
+	}
+	return reqs
+}
+
+// indexReviewByProposal indexes reviews by the namespace/name key of the
+// proposal they review.
+func (r *ProposalReconciler) indexReviewByProposal(o client.Object) []string {
+	review, ok := o.(*talksv1.Review)
+	if !ok {
+		panic(fmt.Sprintf("Expected a Review, got %T", o))
+	}
+
+	return []string{proposalKey(review).String()}
+}
+
+func (r *ProposalReconciler) requestsForReviewChange(o client.Object) []reconcile.Request {
+	review, ok := o.(*talksv1.Review)
+	if !ok {
+		panic(fmt.Sprintf("Expected a Review, got %T", o))
+	}
+
+	return []reconcile.Request{{NamespacedName: proposalKey(review)}}
 }
 
 func (r *ProposalReconciler) requestsForSpeakerChange(o client.Object) []reconcile.Request {
@@ -99,8 +225,8 @@ func (r *ProposalReconciler) requestsForSpeakerChange(o client.Object) []reconci
 	}
 
 	ctx := context.Background()
-	var list talksv1.ProposalList
-	if err := r.List(ctx, &list, client.MatchingFields{talksv1.SpeakerIndexKey: speaker.Name}); err != nil {
+	var list talksv2.ProposalList
+	if err := r.List(ctx, &list, client.MatchingFields{talksv1.SpeakerIndexKey: client.ObjectKeyFromObject(speaker).String()}); err != nil {
 		return nil
 	}
 
@@ -114,6 +240,8 @@ func (r *ProposalReconciler) requestsForSpeakerChange(o client.Object) []reconci
 //+kubebuilder:rbac:groups=talks.kubecon.na,resources=proposals,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=talks.kubecon.na,resources=proposals/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=talks.kubecon.na,resources=proposals/finalizers,verbs=update
+//+kubebuilder:rbac:groups=talks.kubecon.na,resources=conferences,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -125,16 +253,25 @@ func (r *ProposalReconciler) requestsForSpeakerChange(o client.Object) []reconci
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.12.2/pkg/reconcile
 func (r *ProposalReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, retErr error) {
+	ctx, span := startReconcileSpan(ctx, "Proposal", req)
+	defer func() { endSpan(span, retErr) }()
+
 	log := log.FromContext(ctx)
 
 	// Fetch the proposal
 	// Automatically requeue if an error is returned
 	// otherwise requeue based on the result.requeue and result.requeueAfter
-	obj := &talksv1.Proposal{}
+	obj := &talksv2.Proposal{}
 	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	// A stalled object fails the same way until its spec changes, it is not
+	// reconciled again on resync or on the events of other objects
+	if skipStalled(obj, obj.Status.ObservedGeneration) {
+		return ctrl.Result{}, nil
+	}
+
 	log.Info("reconciling proposal", "speaker", obj.Name)
 
 	// Initialize the patch helper with the current version of the object.
@@ -181,12 +318,46 @@ func (r *ProposalReconciler) Reconcile(ctx context.Context, req ctrl.Request) (r
 		return ctrl.Result{Requeue: true}, nil
 	}
 
+	// Skip the calls to the CFP API while the object is suspended.
+	// A suspended object can still be deleted, in which case its finalizer is
+	// removed without deleting it from the CFP API.
+	if obj.Spec.Suspend {
+		if !obj.ObjectMeta.DeletionTimestamp.IsZero() {
+			controllerutil.RemoveFinalizer(obj, talksv1.Finalizer)
+			return ctrl.Result{}, nil
+		}
+		log.Info("reconciliation is suspended for this object")
+		r.EventRecorder.Event(obj, corev1.EventTypeNormal, meta.SuspendedReason, "reconciliation is suspended")
+		conditions.MarkFalse(obj, meta.ReadyCondition, meta.SuspendedReason, "reconciliation is suspended")
+		return ctrl.Result{}, nil
+	}
+
+	// Get the address of the CFP API holding the record of the proposal.
+	// Only the allowed endpoints are sent requests: a proposal being deleted
+	// keeps its finalizer until the endpoint of its record is allowed again.
+	conference, endpoint, err := r.resolveEndpoint(ctx, obj)
+	if err != nil {
+		var stallErr *stallingError
+		if errors.As(err, &stallErr) {
+			conditions.MarkStalled(obj, stallErr.Reason, err.Error())
+			conditions.MarkFalse(obj, meta.ReadyCondition, stallErr.Reason, err.Error())
+			r.EventRecorder.Event(obj, corev1.EventTypeWarning, stallErr.Reason, err.Error())
+			return ctrl.Result{}, nil
+		}
+		conditions.MarkTrue(obj, talksv1.FetchFailedCondition, talksv1.FetchFailedReason, err.Error())
+		conditions.MarkFalse(obj, meta.ReadyCondition, meta.FailedReason, err.Error())
+		r.EventRecorder.Event(obj, corev1.EventTypeWarning, talksv1.FetchFailedReason, err.Error())
+		return ctrl.Result{}, err
+	}
+
 	//create a new propsal client
-	cfpClient, err := cfp.NewClient(r.CfpAPI, r.HTTPClient)
+	cfpClient, err := cfp.NewClient(endpoint, r.HTTPClient)
 	if err != nil {
 		conditions.MarkStalled(obj, talksv1.CreateFailedCondition, "Failed to create CFP client")
 		return ctrl.Result{}, err
 	}
+	cfpClient.Limiter = r.RateLimiter
+	cfpClient.Credentials = r.Credentials
 
 	// Check if this a deletion, if yes api call to delete the Speaker
 	if !obj.ObjectMeta.DeletionTimestamp.IsZero() {
@@ -194,16 +365,39 @@ func (r *ProposalReconciler) Reconcile(ctx context.Context, req ctrl.Request) (r
 	}
 
 	// Perform reconciliation logic
-	result, retErr = r.reconcile(ctx, obj, cfpClient)
+	result, retErr = r.reconcile(ctx, obj, conference, cfpClient)
 
 	return
 }
 
-func (r *ProposalReconciler) reconcile(ctx context.Context, obj *talksv1.Proposal, client *cfp.Client) (result ctrl.Result, retErr error) {
+func (r *ProposalReconciler) reconcile(ctx context.Context, obj *talksv2.Proposal, conference *talksv1.Conference, client *cfp.Client) (result ctrl.Result, retErr error) {
 	// defer func attempt to set the Ready condition and unset all needed conditions based on the reconciliation
 	defer func() {
+		// A stalled reconciliation is not retried until the object changes,
+		// a waiting one is retried once the wait is over.
+		retErr = classifyError(retErr)
+		var (
+			stallErr *stallingError
+			waitErr  *waitingError
+		)
+		switch {
+		case errors.As(retErr, &stallErr):
+			conditions.MarkStalled(obj, stallErr.Reason, stallErr.Error())
+			conditions.MarkFalse(obj, meta.ReadyCondition, stallErr.Reason, stallErr.Error())
+			r.EventRecorder.Event(obj, corev1.EventTypeWarning, stallErr.Reason, stallErr.Error())
+			result, retErr = ctrl.Result{}, nil
+			return
+		case errors.As(retErr, &waitErr):
+			conditions.Delete(obj, meta.StalledCondition)
+			conditions.MarkFalse(obj, meta.ReadyCondition, waitErr.Reason, waitErr.Error())
+			r.EventRecorder.Event(obj, corev1.EventTypeNormal, waitErr.Reason, waitErr.Error())
+			result, retErr = ctrl.Result{RequeueAfter: waitErr.RequeueAfter}, nil
+			return
+		}
+
 		if !result.Requeue && retErr == nil {
 			conditions.Delete(obj, meta.ReconcilingCondition)
+			conditions.Delete(obj, meta.StalledCondition)
 			conditions.Delete(obj, talksv1.CreateFailedCondition)
 			conditions.Delete(obj, talksv1.UpdateFailedCondition)
 			conditions.Delete(obj, talksv1.FetchFailedCondition)
@@ -215,16 +409,17 @@ func (r *ProposalReconciler) reconcile(ctx context.Context, obj *talksv1.Proposa
 			if ok := errors.As(retErr, &apiErr); ok {
 				switch apiErr.Reason {
 				case cfp.ErrCreateProposal:
-					conditions.MarkTrue(obj, talksv1.CreateFailedCondition, apiErr.Reason.Reason, apiErr.Error())
-					conditions.MarkFalse(obj, meta.ReadyCondition, apiErr.Reason.Reason, apiErr.Error())
+					conditions.MarkTrue(obj, talksv1.CreateFailedCondition, apiErrorReason(apiErr), apiErr.Error())
+					conditions.MarkFalse(obj, meta.ReadyCondition, apiErrorReason(apiErr), apiErr.Error())
 				case cfp.ErrUpdateProposal:
-					conditions.MarkTrue(obj, talksv1.UpdateFailedCondition, apiErr.Reason.Reason, apiErr.Error())
-					conditions.MarkFalse(obj, meta.ReadyCondition, apiErr.Reason.Reason, apiErr.Error())
+					conditions.MarkTrue(obj, talksv1.UpdateFailedCondition, apiErrorReason(apiErr), apiErr.Error())
+					conditions.MarkFalse(obj, meta.ReadyCondition, apiErrorReason(apiErr), apiErr.Error())
 				case cfp.ErrCreateRequest, cfp.ErrMakeRequest, cfp.ErrFetchProposal:
-					conditions.MarkFalse(obj, meta.ReadyCondition, apiErr.Reason.Reason, apiErr.Error())
+					conditions.MarkFalse(obj, meta.ReadyCondition, apiErrorReason(apiErr), apiErr.Error())
 				default:
 					conditions.MarkFalse(obj, meta.ReadyCondition, meta.FailedReason, apiErr.Error())
 				}
+				r.EventRecorder.Event(obj, corev1.EventTypeWarning, conditions.GetReason(obj, meta.ReadyCondition), apiErr.Error())
 			}
 		}
 	}()
@@ -234,151 +429,441 @@ func (r *ProposalReconciler) reconcile(ctx context.Context, obj *talksv1.Proposa
 		conditions.MarkReconciling(obj, meta.ProgressingReason, fmt.Sprintf("Reconciling a new generation of the object %d", obj.Generation))
 	}
 
-	// Get the speaker reference
-	speaker := &talksv1.Speaker{}
-	namespacedName := types.NamespacedName{Namespace: obj.Namespace, Name: obj.Spec.SpeakerRef.Name}
-	if obj.Spec.SpeakerRef.Namespace != "" {
-		namespacedName.Namespace = obj.Spec.SpeakerRef.Namespace
-	}
-
-	if err := r.Get(ctx, namespacedName, speaker); err != nil {
-		err = fmt.Errorf("unable to get speaker %s: %w", namespacedName.String(), err)
+	// Resolve the IDs of all the referenced speakers, the primary speaker first
+	speakerIDs, err := r.getSpeakerIDs(ctx, obj, client.Endpoint())
+	if err != nil {
+		var stallErr *stallingError
+		if errors.As(err, &stallErr) {
+			return ctrl.Result{}, err
+		}
 		conditions.MarkTrue(obj, talksv1.FetchFailedCondition, talksv1.FetchFailedCondition, err.Error())
 		conditions.MarkFalse(obj, meta.ReadyCondition, meta.FailedReason, err.Error())
+		r.EventRecorder.Event(obj, corev1.EventTypeWarning, talksv1.FetchFailedReason, err.Error())
 		return ctrl.Result{}, err
 	}
 
-	if speaker.Status.ID == "" {
-		err := fmt.Errorf("unable to get speaker %s", namespacedName.String())
-		conditions.MarkTrue(obj, talksv1.FetchFailedCondition, talksv1.FetchFailedCondition, err.Error())
-		conditions.MarkFalse(obj, meta.ReadyCondition, meta.FailedReason, err.Error())
-		return ctrl.Result{}, err
+	// Check that the proposal can be submitted to the conference
+	if conference != nil {
+		if err := checkCallForPapers(obj, conference, time.Now()); err != nil {
+			return ctrl.Result{}, err
+		}
 	}
 
-	speakerID := speaker.Status.ID
+	// Create the Proposal if it has not been submitted yet
+	var response *cfptypes.Proposal
+	if obj.Status.Submission == "" {
+		response, err = r.createProposal(ctx, obj, speakerIDs, client)
+		switch {
+		case cfp.IsConflict(err):
+			// The record may have been created by a previous reconciliation
+			// whose status was lost, in which case it carries the owner marker
+			// of the Proposal and is adopted
+			remote, getErr := client.Proposals().Get(ctx, proposalID(obj))
+			if getErr != nil {
+				return ctrl.Result{}, getErr
+			}
+			// Creating the proposal again can not succeed while its ID is taken
+			if remote.Owner != ownerMarker(r.ClusterID, obj) {
+				return ctrl.Result{}, &stallingError{Reason: talksv1.AlreadyExistsReason, Err: err}
+			}
+			r.EventRecorder.Eventf(obj, corev1.EventTypeNormal, proposalAdoptedReason, "adopted its own proposal '%s-%s' of the CFP API", obj.Namespace, obj.Name)
+			// Converge the record from its submission status, so that a final
+			// record is never turned back into a draft
+			obj.Status.Submission = remote.Submission.Status
+			obj.Status.Endpoint = client.Endpoint()
+		case err != nil:
+			return ctrl.Result{}, err
+		default:
+			obj.Status.Submission = response.Submission.Status
+			obj.Status.Endpoint = client.Endpoint()
+			obj.Status.LastUpdate = metav1.Time{Time: response.Submission.LastUpdate}
+			obj.Status.LastSyncTime = &metav1.Time{Time: time.Now()}
+			return ctrl.Result{RequeueAfter: r.requeueAfter(obj)}, r.reconcileReviews(ctx, obj)
+		}
+	}
 
 	// If we have a Submission on the ProposalStatus sub resource
 	// Check if an update is needed
 	// If the proposal is marked final, and the submission status is not final, create an entry in cfp.
-	var (
-		response *ProposalObject
-		err      error
-	)
-	if obj.Status.Submission != "" {
-		response, err = r.updateSubmission(ctx, obj, speakerID, client)
-		if err != nil {
-			return ctrl.Result{}, err
+	response, err = r.updateSubmission(ctx, obj, speakerIDs, client)
+	if cfp.IsNotFound(err) {
+		// The record of the Proposal was deleted outside of the cluster,
+		// create it again
+		r.EventRecorder.Eventf(obj, corev1.EventTypeWarning, proposalRecreatedReason,
+			"proposal '%s' was deleted from the CFP API outside of the cluster, recreating it", proposalID(obj))
+		response, err = r.createProposal(ctx, obj, speakerIDs, client)
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if response != nil {
+		obj.Status.Submission = response.Submission.Status
+		obj.Status.LastUpdate = metav1.Time{Time: response.Submission.LastUpdate}
+	}
+	obj.Status.Endpoint = client.Endpoint()
+	obj.Status.LastSyncTime = &metav1.Time{Time: time.Now()}
+	return ctrl.Result{RequeueAfter: r.requeueAfter(obj)}, r.reconcileReviews(ctx, obj)
+}
+
+// requeueAfter returns the interval at which the Proposal is compared with
+// its record in the CFP API.
+func (r *ProposalReconciler) requeueAfter(obj *talksv2.Proposal) time.Duration {
+	if d := obj.GetRequeueAfter(); d > 0 {
+		return d
+	}
+	return r.DefaultInterval
+}
+
+// reconcileReviews aggregates the reviews of the Proposal into its status.
+// The Reviewed condition is True once the proposal has been submitted as
+// final and has at least MinReviews reviews.
+func (r *ProposalReconciler) reconcileReviews(ctx context.Context, obj *talksv2.Proposal) error {
+	var list talksv1.ReviewList
+	if err := r.List(ctx, &list, client.MatchingFields{talksv1.ProposalIndexKey: client.ObjectKeyFromObject(obj).String()}); err != nil {
+		return fmt.Errorf("unable to list reviews: %w", err)
+	}
+
+	count, total := 0, 0
+	for _, review := range list.Items {
+		if !review.DeletionTimestamp.IsZero() {
+			continue
 		}
+		count++
+		total += review.Spec.Score
+	}
 
-		if response != nil {
-			obj.Status.Submission = response.Submission.Status
-			obj.Status.LastUpdate = metav1.Time{Time: response.Submission.LastUpdate}
+	obj.Status.ReviewCount = count
+	obj.Status.MeanScore = ""
+	if count > 0 {
+		obj.Status.MeanScore = fmt.Sprintf("%.2f", float64(total)/float64(count))
+	}
+	obj.Status.DecisionReady = obj.Status.Submission == talksv1.ProposalStateFinal && count >= r.MinReviews
+
+	if obj.Status.DecisionReady {
+		conditions.MarkTrue(obj, talksv1.ReviewedCondition, talksv1.DecisionReadyReason,
+			"%d reviews with a mean score of %s", count, obj.Status.MeanScore)
+	} else {
+		conditions.MarkFalse(obj, talksv1.ReviewedCondition, talksv1.ReviewsPendingReason,
+			"%d of %d reviews", count, r.MinReviews)
+	}
+	return nil
+}
+
+// resolveEndpoint returns the conference of the Proposal, if any, and the
+// address of the CFP API holding its record. The record of a Proposal being
+// deleted is deleted from the CFP API recorded in its status, rather than from
+// the one of its conference which may be gone or have changed.
+func (r *ProposalReconciler) resolveEndpoint(ctx context.Context, obj *talksv2.Proposal) (*talksv1.Conference, string, error) {
+	if !obj.ObjectMeta.DeletionTimestamp.IsZero() {
+		switch {
+		case obj.Status.Endpoint != "":
+			endpoint, err := recordedEndpoint(obj.Status.Endpoint, r.CfpAPI, r.AllowedEndpoints)
+			return nil, endpoint, err
+		case obj.Status.Submission == "":
+			// There is no record to delete from the CFP API
+			return nil, r.CfpAPI, nil
 		}
-		return ctrl.Result{}, nil
 	}
 
-	// Create the Proposal
-	response, err = r.createProposal(ctx, obj, speakerID, client)
+	conference, err := r.getConference(ctx, obj)
 	if err != nil {
-		return ctrl.Result{}, err
+		return nil, "", err
 	}
+	endpoint, err := cfpEndpoint(conference, r.CfpAPI, r.AllowedEndpoints)
+	return conference, endpoint, err
+}
 
-	obj.Status.Submission = response.Submission.Status
-	obj.Status.LastUpdate = metav1.Time{Time: response.Submission.LastUpdate}
+// getConference returns the conference referenced by the Proposal, or nil if
+// the Proposal does not reference any.
+func (r *ProposalReconciler) getConference(ctx context.Context, obj *talksv2.Proposal) (*talksv1.Conference, error) {
+	if obj.Spec.ConferenceRef == nil {
+		return nil, nil
+	}
 
-	return ctrl.Result{}, nil
+	conference := &talksv1.Conference{}
+	namespacedName := conferenceKey(obj)
+	if err := r.Get(ctx, namespacedName, conference); err != nil {
+		return nil, fmt.Errorf("unable to get conference %s: %w", namespacedName.String(), err)
+	}
+	return conference, nil
 }
 
-func (r *ProposalReconciler) createProposal(ctx context.Context, obj *talksv1.Proposal, speakerID string, client *cfp.Client) (*ProposalObject, error) {
-	submissionStatus := talksv1.ProposalStateDraft
-	if obj.Spec.Final {
-		submissionStatus = talksv1.ProposalStateFinal
+// checkCallForPapers returns an error if the proposal can not be submitted to
+// the conference at the given time.
+// Nothing is submitted before the call for papers opens, and the proposal is
+// reconciled again as soon as it opens. Once it is closed, the proposal can no
+// longer be submitted as final.
+func checkCallForPapers(obj *talksv2.Proposal, conference *talksv1.Conference, now time.Time) error {
+	if now.Before(conference.Spec.OpenTime.Time) {
+		return &waitingError{
+			Reason:       talksv1.CFPNotOpenReason,
+			RequeueAfter: conference.Spec.OpenTime.Sub(now),
+			Err: fmt.Errorf("the call for papers of conference '%s' opens at %s",
+				conference.Name, conference.Spec.OpenTime.UTC().Format(time.RFC3339)),
+		}
 	}
 
-	proposal, err := createProposalPayload(obj, speakerID, submissionStatus)
-	if err != nil {
-		return nil, err
+	if !conference.AcceptsTalkType(obj.Spec.Type) {
+		return &stallingError{
+			Reason: talksv1.TalkTypeNotAcceptedReason,
+			Err: fmt.Errorf("the call for papers of conference '%s' does not accept talks of type '%s'",
+				conference.Name, obj.Spec.Type),
+		}
 	}
 
-	// Create the proposal
-	resp, err := client.Create(ctx, cfp.ProposalPath, proposal)
-	if err != nil {
-		return nil, err
+	finalizing := obj.Spec.Final && obj.Status.Submission != talksv1.ProposalStateFinal
+	if finalizing && !now.Before(conference.Spec.CloseTime.Time) {
+		return &stallingError{
+			Reason: talksv1.CFPClosedReason,
+			Err: fmt.Errorf("the call for papers of conference '%s' closed at %s",
+				conference.Name, conference.Spec.CloseTime.UTC().Format(time.RFC3339)),
+		}
 	}
 
-	p := &ProposalObject{}
-	err = json.Unmarshal(resp, p)
+	return nil
+}
+
+// conferenceKey returns the namespace/name of the conference referenced by
+// the Proposal, which defaults to the namespace of the Proposal.
+func conferenceKey(obj *talksv2.Proposal) types.NamespacedName {
+	namespacedName := types.NamespacedName{Namespace: obj.Namespace, Name: obj.Spec.ConferenceRef.Name}
+	if obj.Spec.ConferenceRef.Namespace != "" {
+		namespacedName.Namespace = obj.Spec.ConferenceRef.Namespace
+	}
+	return namespacedName
+}
+
+// speakerKey returns the namespace/name of a speaker referenced by the
+// Proposal, which defaults to the namespace of the Proposal.
+func speakerKey(obj *talksv2.Proposal, ref talksv2.SpeakerRef) types.NamespacedName {
+	namespacedName := types.NamespacedName{Namespace: obj.Namespace, Name: ref.Name}
+	if ref.Namespace != "" {
+		namespacedName.Namespace = ref.Namespace
+	}
+	return namespacedName
+}
+
+// getSpeakerIDs returns the CFP API IDs of the speakers referenced by the
+// Proposal, in the order of the references. It fails if any of the speakers
+// does not exist or has not been registered in the CFP API yet.
+func (r *ProposalReconciler) getSpeakerIDs(ctx context.Context, obj *talksv2.Proposal, endpoint string) ([]string, error) {
+	var speakerIDs []string
+	for _, ref := range obj.Spec.SpeakerRefs {
+		speaker := &talksv1.Speaker{}
+		namespacedName := speakerKey(obj, ref)
+
+		if err := r.getSpeaker(ctx, namespacedName, speaker); err != nil {
+			// The speakers of an already reconciled generation have been
+			// resolved before, a missing one has been deleted since.
+			if apierrors.IsNotFound(err) && obj.Generation == obj.Status.ObservedGeneration {
+				return nil, &stallingError{
+					Reason: talksv1.SpeakerDeletedReason,
+					Err:    fmt.Errorf("speaker %s has been deleted", namespacedName.String()),
+				}
+			}
+			return nil, fmt.Errorf("unable to get speaker %s: %w", namespacedName.String(), err)
+		}
+
+		if !speaker.DeletionTimestamp.IsZero() {
+			return nil, &stallingError{
+				Reason: talksv1.SpeakerDeletedReason,
+				Err:    fmt.Errorf("speaker %s is being deleted", namespacedName.String()),
+			}
+		}
+
+		if err := r.checkSpeakerAccess(ctx, obj, speaker); err != nil {
+			return nil, err
+		}
+
+		if speaker.Status.ID == "" {
+			return nil, fmt.Errorf("unable to get speaker %s", namespacedName.String())
+		}
+
+		// The CFP API only accepts the speakers it holds the record of
+		if !sameEndpoint(speakerEndpoint(speaker, r.CfpAPI), endpoint) {
+			return nil, &stallingError{
+				Reason: talksv1.EndpointMismatchReason,
+				Err: fmt.Errorf("speaker %s is recorded in the CFP API '%s', not in '%s' where the proposal is submitted",
+					namespacedName.String(), speakerEndpoint(speaker, r.CfpAPI), endpoint),
+			}
+		}
+
+		speakerIDs = append(speakerIDs, speaker.Status.ID)
+	}
+
+	if len(speakerIDs) == 0 {
+		return nil, fmt.Errorf("no speaker referenced")
+	}
+
+	return speakerIDs, nil
+}
+
+// getSpeaker gets a Speaker referenced by the Proposal within a span, so that
+// the trace of a reconciliation shows its Speaker lookups.
+func (r *ProposalReconciler) getSpeaker(ctx context.Context, key types.NamespacedName, speaker *talksv1.Speaker) error {
+	ctx, span := startSpan(ctx, "Get Speaker", attribute.String("speaker", key.String()))
+	err := r.Get(ctx, key, speaker)
+	endSpan(span, err)
+	return err
+}
+
+// checkSpeakerAccess returns a stallingError if the Proposal is not allowed
+// to reference a speaker of another namespace, either because cross-namespace
+// references are disabled or because of the ACL of the speaker.
+func (r *ProposalReconciler) checkSpeakerAccess(ctx context.Context, obj *talksv2.Proposal, speaker *talksv1.Speaker) error {
+	if speaker.Namespace == obj.Namespace {
+		return nil
+	}
+
+	if r.NoCrossNamespaceRefs {
+		return &stallingError{
+			Reason: aclapi.AccessDeniedReason,
+			Err: fmt.Errorf("speaker '%s/%s' can't be accessed: cross-namespace references are not allowed",
+				speaker.Namespace, speaker.Name),
+		}
+	}
+
+	err := acl.NewAuthorization(r.Client).HasAccessToRef(ctx, obj, client.ObjectKeyFromObject(speaker), speaker.Spec.AccessFrom)
+	if acl.IsAccessDenied(err) {
+		return &stallingError{
+			Reason: aclapi.AccessDeniedReason,
+			Err:    fmt.Errorf("speaker %w", err),
+		}
+	}
+	return err
+}
+
+func (r *ProposalReconciler) createProposal(ctx context.Context, obj *talksv2.Proposal, speakerIDs []string, client *cfp.Client) (*cfptypes.Proposal, error) {
+	submissionStatus := talksv1.ProposalStateDraft
+	if obj.Spec.Final {
+		submissionStatus = talksv1.ProposalStateFinal
+	}
+
+	// Create the proposal
+	p, err := client.Proposals().Create(ctx, proposalRecord(obj, speakerIDs, submissionStatus, ownerMarker(r.ClusterID, obj)))
 	if err != nil {
 		return nil, err
 	}
+	r.EventRecorder.Eventf(obj, corev1.EventTypeNormal, proposalCreatedReason, "created %s proposal '%s-%s' in the CFP API", submissionStatus, obj.Namespace, obj.Name)
 
 	return p, nil
 }
 
-func (r *ProposalReconciler) updateSubmission(ctx context.Context, obj *talksv1.Proposal, speakerID string, client *cfp.Client) (*ProposalObject, error) {
+func (r *ProposalReconciler) updateSubmission(ctx context.Context, obj *talksv2.Proposal, speakerIDs []string, client *cfp.Client) (*cfptypes.Proposal, error) {
 	switch obj.Status.Submission {
 	case talksv1.ProposalStateDraft:
 		// If the proposal is marked final, and the submission status is not final, create an entry in cfp.
 		if obj.Spec.Final {
-			// Create a draft proposal
-			proposal, err := createProposalPayload(obj, speakerID, talksv1.ProposalStateFinal)
-			if err != nil {
-				return nil, err
-			}
-			// Update the proposal
-			resp, err := client.Update(ctx, cfp.ProposalPath, fmt.Sprintf("%s-%s", obj.Namespace, obj.Name), proposal)
-			if err != nil {
-				return nil, err
-			}
-
-			p := &ProposalObject{}
-			err = json.Unmarshal(resp, p)
+			// Update the proposal, as long as it has not been modified since
+			// it was read
+			var p *cfptypes.Proposal
+			err := retry.OnError(retry.DefaultRetry, cfp.IsPreconditionFailed, func() error {
+				remote, err := client.Proposals().Get(ctx, proposalID(obj))
+				if err != nil {
+					return err
+				}
+				record := proposalRecord(obj, speakerIDs, talksv1.ProposalStateFinal, ownerMarker(r.ClusterID, obj))
+				record.ETag = remote.ETag
+				p, err = client.Proposals().Update(ctx, record)
+				return err
+			})
 			if err != nil {
 				return nil, err
 			}
+			r.EventRecorder.Eventf(obj, corev1.EventTypeNormal, proposalFinalizedReason, "submitted proposal '%s-%s' as final to the CFP API", obj.Namespace, obj.Name)
 			return p, nil
-		} else {
-			// Check if the proposal content needs to be updated
-			content, err := client.Get(ctx, cfp.ProposalPath, fmt.Sprintf("%s-%s", obj.Namespace, obj.Name))
-			if err != nil {
-				return nil, err
-			}
-
-			// Create a draft proposal
-			proposal, err := createProposalPayload(obj, speakerID, talksv1.ProposalStateDraft)
-			if err != nil {
-				return nil, err
-			}
-
-			// If the proposal content is not the same, update the proposal
-			if !bytes.Equal(content, proposal) {
-				resp, err := client.Update(ctx, cfp.ProposalPath, fmt.Sprintf("%s-%s", obj.Namespace, obj.Name), proposal)
-				if err != nil {
-					return nil, err
-				}
-				p := &ProposalObject{}
-				err = json.Unmarshal(resp, p)
-				if err != nil {
-					return nil, err
-				}
-				return p, nil
-			}
 		}
+		// Check if the proposal content needs to be updated
+		return r.syncSubmission(ctx, obj, speakerIDs, talksv1.ProposalStateDraft, client)
 	case talksv1.ProposalStateFinal:
 		// If the proposal submission is final, no need to update
-		// Return
+		// Correct the record if it changed in the CFP API
+		return r.syncSubmission(ctx, obj, speakerIDs, talksv1.ProposalStateFinal, client)
 	}
 	return nil, nil
 }
 
-// reconcileDelete will delete the obj from the CFP API if it is still a draft.
-func (r *ProposalReconciler) reconcileDelete(ctx context.Context, obj *talksv1.Proposal, client *cfp.Client) (ctrl.Result, error) {
-	// api call to delete the proposal if it is still a draft
-	if obj.Status.Submission == talksv1.ProposalStateDraft {
-		err := client.Delete(ctx, cfp.ProposalPath, fmt.Sprintf("%s-%s", obj.Namespace, obj.Name))
+// syncSubmission compares the fields of the proposal record owned by the
+// Proposal, and updates the record if any of them differs.
+// A difference while the spec did not change since the last reconciliation is
+// a drift of the record, which is reported in the Drifted condition until the
+// record matches the Proposal.
+// The record is read and compared again if it is modified before the update.
+func (r *ProposalReconciler) syncSubmission(ctx context.Context, obj *talksv2.Proposal, speakerIDs []string, submission string, client *cfp.Client) (*cfptypes.Proposal, error) {
+	if obj.Generation != obj.Status.ObservedGeneration {
+		conditions.Delete(obj, talksv1.DriftedCondition)
+	}
+
+	var (
+		p       *cfptypes.Proposal
+		drifted []string
+	)
+	err := retry.OnError(retry.DefaultRetry, cfp.IsPreconditionFailed, func() error {
+		remote, err := client.Proposals().Get(ctx, proposalID(obj))
 		if err != nil {
-			// return the error so we can requeue
-			return ctrl.Result{}, err
+			return err
+		}
+
+		drifted = driftedFields(ownedProposalFields(obj, speakerIDs, submission), proposalRecordFields(remote))
+		if len(drifted) == 0 {
+			return nil
+		}
+
+		record := proposalRecord(obj, speakerIDs, submission, ownerMarker(r.ClusterID, obj))
+		record.ETag = remote.ETag
+		p, err = client.Proposals().Update(ctx, record)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	// A record matching the Proposal no longer drifts
+	if len(drifted) == 0 {
+		conditions.Delete(obj, talksv1.DriftedCondition)
+		return nil, nil
+	}
+	r.EventRecorder.Eventf(obj, corev1.EventTypeNormal, proposalUpdatedReason, "updated proposal '%s-%s' in the CFP API", obj.Namespace, obj.Name)
+
+	if obj.Generation == obj.Status.ObservedGeneration {
+		recordDrift("Proposal", drifted)
+		conditions.MarkTrue(obj, talksv1.DriftedCondition, talksv1.DriftCorrectedReason,
+			"corrected drifted fields of the CFP API record: %s", strings.Join(drifted, ", "))
+		r.EventRecorder.Event(obj, corev1.EventTypeWarning, talksv1.DriftCorrectedReason, conditions.GetMessage(obj, talksv1.DriftedCondition))
+	}
+	return p, nil
+}
+
+// reconcileDelete will delete the obj from the CFP API if it is still a draft.
+func (r *ProposalReconciler) reconcileDelete(ctx context.Context, obj *talksv2.Proposal, client *cfp.Client) (ctrl.Result, error) {
+	// Apply the deletion policy to the submitted proposal, by default drafts
+	// are deleted and final proposals are retained
+	policy := obj.Spec.DeletionPolicy
+	if policy == "" {
+		policy = talksv1.DeletionPolicyRetain
+		if obj.Status.Submission == talksv1.ProposalStateDraft {
+			policy = talksv1.DeletionPolicyDelete
+		}
+	}
+
+	if obj.Status.Submission != "" {
+		switch policy {
+		case talksv1.DeletionPolicyDelete:
+			// A record which is not found has already been deleted
+			err := client.Proposals().Delete(ctx, proposalID(obj))
+			if err != nil && !cfp.IsNotFound(err) {
+				r.EventRecorder.Event(obj, corev1.EventTypeWarning, failureReason(err), err.Error())
+				// return the error so we can requeue
+				return ctrl.Result{}, err
+			}
+			r.EventRecorder.Eventf(obj, corev1.EventTypeNormal, proposalDeletedReason, "deleted %s proposal '%s-%s' from the CFP API", obj.Status.Submission, obj.Namespace, obj.Name)
+		case talksv1.DeletionPolicyWithdraw:
+			if err := r.withdrawProposal(ctx, obj, client); err != nil {
+				r.EventRecorder.Event(obj, corev1.EventTypeWarning, failureReason(err), err.Error())
+				// return the error so we can requeue
+				return ctrl.Result{}, err
+			}
+			r.EventRecorder.Eventf(obj, corev1.EventTypeNormal, proposalWithdrawnReason, "withdrew proposal '%s-%s' from the CFP API", obj.Namespace, obj.Name)
 		}
 	}
 	// clean the finalizer
@@ -388,37 +873,78 @@ func (r *ProposalReconciler) reconcileDelete(ctx context.Context, obj *talksv1.P
 	return ctrl.Result{}, nil
 }
 
-func createProposalPayload(obj *talksv1.Proposal, speakerID, submission string) ([]byte, error) {
-	body := ProposalObject{
-		ID:         fmt.Sprintf("%s-%s", obj.Namespace, obj.Name),
+// withdrawProposal marks the record of the proposal in the CFP API as
+// withdrawn, keeping the rest of the record as is. The record is read again
+// if it is modified before the update.
+func (r *ProposalReconciler) withdrawProposal(ctx context.Context, obj *talksv2.Proposal, client *cfp.Client) error {
+	return retry.OnError(retry.DefaultRetry, cfp.IsPreconditionFailed, func() error {
+		proposal, err := client.Proposals().Get(ctx, proposalID(obj))
+		if err != nil {
+			return err
+		}
+
+		if proposal.Submission.Status == talksv1.ProposalStateWithdrawn {
+			return nil
+		}
+		proposal.Submission.Status = talksv1.ProposalStateWithdrawn
+
+		_, err = client.Proposals().Update(ctx, proposal)
+		return err
+	})
+}
+
+// proposalID returns the ID of the record of the Proposal in the CFP API.
+func proposalID(obj *talksv2.Proposal) string {
+	return fmt.Sprintf("%s-%s", obj.Namespace, obj.Name)
+}
+
+// proposalRecord returns the CFP API record of the Proposal.
+func proposalRecord(obj *talksv2.Proposal, speakerIDs []string, submission, owner string) *cfptypes.Proposal {
+	return &cfptypes.Proposal{
+		ID:         proposalID(obj),
 		Title:      obj.Spec.Title,
 		Abstract:   obj.Spec.Abstract,
 		Type:       obj.Spec.Type,
-		SpeakerID:  speakerID,
+		SpeakerID:  speakerIDs[0],
+		SpeakerIDs: speakerIDs,
 		Final:      obj.Spec.Final,
-		Submission: Submission{Status: submission},
-	}
-
-	payload, err := json.Marshal(body)
-	if err != nil {
-		return nil, fmt.Errorf("error marshalling body: %w", err)
+		Submission: cfptypes.Submission{Status: submission},
+		Owner:      owner,
 	}
+}
 
-	return payload, nil
+// proposalFields are the fields of a CFP API proposal record owned by a
+// Proposal.
+type proposalFields struct {
+	Title      string   `json:"title"`
+	Abstract   string   `json:"abstract"`
+	Type       string   `json:"type"`
+	SpeakerID  string   `json:"speakerID"`
+	SpeakerIDs []string `json:"speakerIDs"`
+	Final      bool     `json:"final"`
+	Submission string   `json:"submission"`
 }
 
-// ProposalObject is the object that is sent to/received from the CFP API
-type ProposalObject struct {
-	ID         string     `json:"id"`
-	Title      string     `json:"title"`
-	Abstract   string     `json:"abstract"`
-	Type       string     `json:"type"`
-	SpeakerID  string     `json:"speakerID"`
-	Final      bool       `json:"final"`
-	Submission Submission `json:"submission"`
+func ownedProposalFields(obj *talksv2.Proposal, speakerIDs []string, submission string) proposalFields {
+	return proposalFields{
+		Title:      obj.Spec.Title,
+		Abstract:   obj.Spec.Abstract,
+		Type:       obj.Spec.Type,
+		SpeakerID:  speakerIDs[0],
+		SpeakerIDs: speakerIDs,
+		Final:      obj.Spec.Final,
+		Submission: submission,
+	}
 }
 
-type Submission struct {
-	Status     string    `json:"status"`
-	LastUpdate time.Time `json:"lastUpdate"`
+func proposalRecordFields(p *cfptypes.Proposal) proposalFields {
+	return proposalFields{
+		Title:      p.Title,
+		Abstract:   p.Abstract,
+		Type:       p.Type,
+		SpeakerID:  p.SpeakerID,
+		SpeakerIDs: p.SpeakerIDs,
+		Final:      p.Final,
+		Submission: p.Submission.Status,
+	}
 }
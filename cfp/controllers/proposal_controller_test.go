@@ -1,19 +1,26 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"os"
 	"strings"
 	"testing"
+	"time"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	aclapi "github.com/fluxcd/pkg/apis/acl"
 	"github.com/fluxcd/pkg/apis/meta"
 	"github.com/fluxcd/pkg/runtime/conditions"
 	"github.com/fluxcd/pkg/runtime/patch"
 	. "github.com/onsi/gomega"
 	talksv1 "github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/api/v1"
+	talksv2 "github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/api/v2"
+	"github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/internal/cfp"
 )
 
 func Test_Proposal_Reconcile(t *testing.T) {
@@ -26,8 +33,8 @@ func Test_Proposal_Reconcile(t *testing.T) {
 		proposalType     string
 		final            bool
 		assertConditions []metav1.Condition
-		beforeFunc       func(obj *talksv1.Proposal)
-		assertFunc       func(obj *talksv1.Proposal, speaker *talksv1.Speaker, assertConditions []metav1.Condition)
+		beforeFunc       func(obj *talksv2.Proposal)
+		assertFunc       func(obj *talksv2.Proposal, speaker *talksv1.Speaker, assertConditions []metav1.Condition)
 	}{
 		{
 			name:         "test create proposal reconciliation without existing speaker",
@@ -39,8 +46,9 @@ func Test_Proposal_Reconcile(t *testing.T) {
 				*conditions.TrueCondition(meta.ReconcilingCondition, meta.ProgressingReason, "Reconciling a new generation of the object <generation>"),
 				*conditions.FalseCondition(meta.ReadyCondition, meta.FailedReason, "unable to get speaker <namespacedName>: <group> \"<name>\" not found"),
 				*conditions.TrueCondition(talksv1.FetchFailedCondition, talksv1.FetchFailedReason, "unable to get speaker <namespacedName>: <group> \"<name>\" not found"),
+				*conditions.FalseCondition(talksv1.ReviewedCondition, talksv1.ReviewsPendingReason, "0 of 2 reviews"),
 			},
-			beforeFunc: func(obj *talksv1.Proposal) {
+			beforeFunc: func(obj *talksv2.Proposal) {
 				key := client.ObjectKey{Name: obj.Name, Namespace: obj.Namespace}
 				// Wait for Speaker to be Ready
 				g.Eventually(func() bool {
@@ -58,12 +66,12 @@ func Test_Proposal_Reconcile(t *testing.T) {
 				patch, err := patch.NewHelper(obj, testEnv)
 				g.Expect(err).ToNot(HaveOccurred())
 				// Set wrong speaker name
-				obj.Spec.SpeakerRef = &talksv1.SpeakerRef{
-					Name: "unkown",
+				obj.Spec.SpeakerRefs = []talksv2.SpeakerRef{
+					{Name: "unkown"},
 				}
 				g.Expect(patch.Patch(ctx, obj)).To(Succeed())
 			},
-			assertFunc: func(obj *talksv1.Proposal, _ *talksv1.Speaker, assertConditions []metav1.Condition) {
+			assertFunc: func(obj *talksv2.Proposal, _ *talksv1.Speaker, assertConditions []metav1.Condition) {
 				key := client.ObjectKey{Name: obj.Name, Namespace: obj.Namespace}
 				// Wait for finalizer to be set
 				g.Eventually(func() bool {
@@ -85,9 +93,9 @@ func Test_Proposal_Reconcile(t *testing.T) {
 				}, timeout).Should(BeTrue())
 
 				for k := range assertConditions {
-					assertConditions[k].Message = strings.ReplaceAll(assertConditions[k].Message, "<name>", obj.Spec.SpeakerRef.Name)
+					assertConditions[k].Message = strings.ReplaceAll(assertConditions[k].Message, "<name>", obj.Spec.SpeakerRefs[0].Name)
 					assertConditions[k].Message = strings.ReplaceAll(assertConditions[k].Message, "<generation>", fmt.Sprint(obj.Generation))
-					assertConditions[k].Message = strings.ReplaceAll(assertConditions[k].Message, "<namespacedName>", fmt.Sprintf("%s/%s", obj.Namespace, obj.Spec.SpeakerRef.Name))
+					assertConditions[k].Message = strings.ReplaceAll(assertConditions[k].Message, "<namespacedName>", fmt.Sprintf("%s/%s", obj.Namespace, obj.Spec.SpeakerRefs[0].Name))
 					assertConditions[k].Message = strings.ReplaceAll(assertConditions[k].Message, "<group>", fmt.Sprintf("Speaker.%s", talksv1.GroupVersion.Group))
 				}
 				g.Expect(obj.Status.Conditions).To(conditions.MatchConditions(assertConditions))
@@ -102,8 +110,9 @@ func Test_Proposal_Reconcile(t *testing.T) {
 			final:        true,
 			assertConditions: []metav1.Condition{
 				*conditions.TrueCondition(meta.ReadyCondition, meta.SucceededReason, "reconciled '<name>' successfully"),
+				*conditions.FalseCondition(talksv1.ReviewedCondition, talksv1.ReviewsPendingReason, "0 of 2 reviews"),
 			},
-			assertFunc: func(obj *talksv1.Proposal, _ *talksv1.Speaker, assertConditions []metav1.Condition) {
+			assertFunc: func(obj *talksv2.Proposal, _ *talksv1.Speaker, assertConditions []metav1.Condition) {
 				key := client.ObjectKey{Name: obj.Name, Namespace: obj.Namespace}
 				// Wait for Proposal to be Ready
 				g.Eventually(func() bool {
@@ -125,6 +134,70 @@ func Test_Proposal_Reconcile(t *testing.T) {
 
 			},
 		},
+		{
+			name:         "test create proposal reconciliation with co-speakers",
+			title:        "this is a test proposal",
+			abstract:     "this is a test abstract",
+			proposalType: "talk",
+			final:        true,
+			assertConditions: []metav1.Condition{
+				*conditions.TrueCondition(meta.ReadyCondition, meta.SucceededReason, "reconciled '<name>' successfully"),
+				*conditions.FalseCondition(talksv1.ReviewedCondition, talksv1.ReviewsPendingReason, "0 of 2 reviews"),
+			},
+			beforeFunc: func(obj *talksv2.Proposal) {
+				coSpeaker := &talksv1.Speaker{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "co-speaker",
+						Namespace: obj.Namespace,
+					},
+					Spec: talksv1.SpeakerSpec{
+						Name:  "co-speaker",
+						Bio:   "test co-speaker",
+						Email: "test.co-speaker@gmail.com",
+					},
+				}
+				g.Expect(testEnv.CreateAndWait(ctx, coSpeaker)).To(Succeed())
+
+				// Wait for the co-speaker to be Ready
+				coSpeakerKey := client.ObjectKey{Name: coSpeaker.Name, Namespace: coSpeaker.Namespace}
+				g.Eventually(func() bool {
+					if err := testEnv.Get(ctx, coSpeakerKey, coSpeaker); err != nil {
+						return false
+					}
+					return conditions.IsReady(coSpeaker)
+				}, timeout).Should(BeTrue())
+
+				key := client.ObjectKey{Name: obj.Name, Namespace: obj.Namespace}
+				g.Expect(testEnv.Get(ctx, key, obj)).To(Succeed())
+
+				patch, err := patch.NewHelper(obj, testEnv)
+				g.Expect(err).ToNot(HaveOccurred())
+				// Add the co-speaker
+				obj.Spec.SpeakerRefs = append(obj.Spec.SpeakerRefs, talksv2.SpeakerRef{Name: coSpeaker.Name})
+				g.Expect(patch.Patch(ctx, obj)).To(Succeed())
+			},
+			assertFunc: func(obj *talksv2.Proposal, _ *talksv1.Speaker, assertConditions []metav1.Condition) {
+				key := client.ObjectKey{Name: obj.Name, Namespace: obj.Namespace}
+				// Wait for Proposal to be Ready for the generation with the co-speaker
+				g.Eventually(func() bool {
+					if err := testEnv.Get(ctx, key, obj); err != nil {
+						return false
+					}
+					if !conditions.IsReady(obj) {
+						return false
+					}
+					readyCondition := conditions.Get(obj, meta.ReadyCondition)
+					return obj.Generation == readyCondition.ObservedGeneration &&
+						obj.Generation == obj.Status.ObservedGeneration &&
+						len(obj.Spec.SpeakerRefs) == 2
+				}, timeout).Should(BeTrue())
+
+				for k := range assertConditions {
+					assertConditions[k].Message = strings.ReplaceAll(assertConditions[k].Message, "<name>", obj.Name)
+				}
+				g.Expect(obj.Status.Conditions).To(conditions.MatchConditions(assertConditions))
+			},
+		},
 		{
 			name:         "test update proposal reconciliation from draft to final",
 			title:        "this is a test proposal",
@@ -133,8 +206,9 @@ func Test_Proposal_Reconcile(t *testing.T) {
 			final:        false,
 			assertConditions: []metav1.Condition{
 				*conditions.TrueCondition(meta.ReadyCondition, meta.SucceededReason, "reconciled '<name>' successfully"),
+				*conditions.FalseCondition(talksv1.ReviewedCondition, talksv1.ReviewsPendingReason, "0 of 2 reviews"),
 			},
-			assertFunc: func(obj *talksv1.Proposal, _ *talksv1.Speaker, assertConditions []metav1.Condition) {
+			assertFunc: func(obj *talksv2.Proposal, _ *talksv1.Speaker, assertConditions []metav1.Condition) {
 				key := client.ObjectKey{Name: obj.Name, Namespace: obj.Namespace}
 				// Wait for Proposal to be Ready
 				g.Eventually(func() bool {
@@ -178,13 +252,168 @@ func Test_Proposal_Reconcile(t *testing.T) {
 
 			},
 		},
+		{
+			name:         "test proposal reconciliation before the call for papers opens",
+			title:        "this is a test proposal",
+			abstract:     "this is a test abstract",
+			proposalType: "talk",
+			final:        false,
+			assertConditions: []metav1.Condition{
+				*conditions.FalseCondition(meta.ReadyCondition, talksv1.CFPNotOpenReason, "the call for papers of conference '<conference>' opens at <openTime>"),
+				*conditions.FalseCondition(talksv1.ReviewedCondition, talksv1.ReviewsPendingReason, "0 of 2 reviews"),
+			},
+			beforeFunc: func(obj *talksv2.Proposal) {
+				conference := &talksv1.Conference{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "conference",
+						Namespace: obj.Namespace,
+					},
+					Spec: talksv1.ConferenceSpec{
+						OpenTime:  metav1.NewTime(time.Now().Add(time.Hour)),
+						CloseTime: metav1.NewTime(time.Now().Add(2 * time.Hour)),
+					},
+				}
+				g.Expect(testEnv.CreateAndWait(ctx, conference)).To(Succeed())
+
+				key := client.ObjectKey{Name: obj.Name, Namespace: obj.Namespace}
+				g.Expect(testEnv.Get(ctx, key, obj)).To(Succeed())
+
+				patch, err := patch.NewHelper(obj, testEnv)
+				g.Expect(err).ToNot(HaveOccurred())
+				// Submit the proposal to the conference
+				obj.Spec.ConferenceRef = &talksv2.ConferenceRef{Name: conference.Name}
+				g.Expect(patch.Patch(ctx, obj)).To(Succeed())
+			},
+			assertFunc: func(obj *talksv2.Proposal, _ *talksv1.Speaker, assertConditions []metav1.Condition) {
+				key := client.ObjectKey{Name: obj.Name, Namespace: obj.Namespace}
+				// Wait for Proposal to wait for the call for papers
+				g.Eventually(func() bool {
+					if err := testEnv.Get(ctx, key, obj); err != nil {
+						return false
+					}
+					return conditions.GetReason(obj, meta.ReadyCondition) == talksv1.CFPNotOpenReason
+				}, timeout).Should(BeTrue())
+
+				conference := &talksv1.Conference{}
+				g.Expect(testEnv.Get(ctx, client.ObjectKey{Name: obj.Spec.ConferenceRef.Name, Namespace: obj.Namespace}, conference)).To(Succeed())
+
+				for k := range assertConditions {
+					assertConditions[k].Message = strings.ReplaceAll(assertConditions[k].Message, "<conference>", conference.Name)
+					assertConditions[k].Message = strings.ReplaceAll(assertConditions[k].Message, "<openTime>", conference.Spec.OpenTime.UTC().Format(time.RFC3339))
+				}
+				g.Expect(obj.Status.Conditions).To(conditions.MatchConditions(assertConditions))
+			},
+		},
+		{
+			name:         "test proposal reconciliation after the call for papers closes",
+			title:        "this is a test proposal",
+			abstract:     "this is a test abstract",
+			proposalType: "talk",
+			final:        false,
+			assertConditions: []metav1.Condition{
+				*conditions.TrueCondition(meta.StalledCondition, talksv1.CFPClosedReason, "the call for papers of conference '<conference>' closed at <closeTime>"),
+				*conditions.FalseCondition(meta.ReadyCondition, talksv1.CFPClosedReason, "the call for papers of conference '<conference>' closed at <closeTime>"),
+				*conditions.FalseCondition(talksv1.ReviewedCondition, talksv1.ReviewsPendingReason, "0 of 2 reviews"),
+			},
+			beforeFunc: func(obj *talksv2.Proposal) {
+				key := client.ObjectKey{Name: obj.Name, Namespace: obj.Namespace}
+				// Wait for the draft to be submitted
+				g.Eventually(func() bool {
+					if err := testEnv.Get(ctx, key, obj); err != nil {
+						return false
+					}
+					return conditions.IsReady(obj) && obj.Status.Submission == "draft"
+				}, timeout).Should(BeTrue())
+
+				conference := &talksv1.Conference{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "conference",
+						Namespace: obj.Namespace,
+					},
+					Spec: talksv1.ConferenceSpec{
+						OpenTime:  metav1.NewTime(time.Now().Add(-2 * time.Hour)),
+						CloseTime: metav1.NewTime(time.Now().Add(-time.Hour)),
+					},
+				}
+				g.Expect(testEnv.CreateAndWait(ctx, conference)).To(Succeed())
+
+				patch, err := patch.NewHelper(obj, testEnv)
+				g.Expect(err).ToNot(HaveOccurred())
+				// Submit the proposal as final to the closed conference
+				obj.Spec.ConferenceRef = &talksv2.ConferenceRef{Name: conference.Name}
+				obj.Spec.Final = true
+				g.Expect(patch.Patch(ctx, obj)).To(Succeed())
+			},
+			assertFunc: func(obj *talksv2.Proposal, _ *talksv1.Speaker, assertConditions []metav1.Condition) {
+				key := client.ObjectKey{Name: obj.Name, Namespace: obj.Namespace}
+				// Wait for Proposal to be stalled
+				g.Eventually(func() bool {
+					if err := testEnv.Get(ctx, key, obj); err != nil {
+						return false
+					}
+					return conditions.IsStalled(obj) &&
+						obj.Generation == obj.Status.ObservedGeneration
+				}, timeout).Should(BeTrue())
+
+				g.Expect(obj.Status.Submission).To(Equal("draft"))
+
+				conference := &talksv1.Conference{}
+				g.Expect(testEnv.Get(ctx, client.ObjectKey{Name: obj.Spec.ConferenceRef.Name, Namespace: obj.Namespace}, conference)).To(Succeed())
+
+				for k := range assertConditions {
+					assertConditions[k].Message = strings.ReplaceAll(assertConditions[k].Message, "<conference>", conference.Name)
+					assertConditions[k].Message = strings.ReplaceAll(assertConditions[k].Message, "<closeTime>", conference.Spec.CloseTime.UTC().Format(time.RFC3339))
+				}
+				g.Expect(obj.Status.Conditions).To(conditions.MatchConditions(assertConditions))
+			},
+		},
+		{
+			name:         "test suspend proposal reconciliation",
+			title:        "this is a test proposal",
+			abstract:     "this is a test abstract",
+			proposalType: "talk",
+			final:        false,
+			assertConditions: []metav1.Condition{
+				*conditions.FalseCondition(meta.ReadyCondition, meta.SuspendedReason, "reconciliation is suspended"),
+				*conditions.FalseCondition(talksv1.ReviewedCondition, talksv1.ReviewsPendingReason, "0 of 2 reviews"),
+			},
+			assertFunc: func(obj *talksv2.Proposal, _ *talksv1.Speaker, assertConditions []metav1.Condition) {
+				key := client.ObjectKey{Name: obj.Name, Namespace: obj.Namespace}
+				// Wait for the draft to be submitted
+				g.Eventually(func() bool {
+					if err := testEnv.Get(ctx, key, obj); err != nil {
+						return false
+					}
+					return conditions.IsReady(obj) && obj.Status.Submission == talksv1.ProposalStateDraft
+				}, timeout).Should(BeTrue())
+
+				patch, err := patch.NewHelper(obj, testEnv)
+				g.Expect(err).ToNot(HaveOccurred())
+				// Suspend the proposal, and mark it final
+				obj.Spec.Suspend = true
+				obj.Spec.Final = true
+				g.Expect(patch.Patch(ctx, obj)).To(Succeed())
+
+				// Wait for Proposal to be suspended
+				g.Eventually(func() bool {
+					if err := testEnv.Get(ctx, key, obj); err != nil {
+						return false
+					}
+					return conditions.GetReason(obj, meta.ReadyCondition) == meta.SuspendedReason
+				}, timeout).Should(BeTrue())
+
+				// The final proposal has not been submitted
+				g.Expect(obj.Status.Submission).To(Equal(talksv1.ProposalStateDraft))
+				g.Expect(obj.Status.Conditions).To(conditions.MatchConditions(assertConditions))
+			},
+		},
 		{
 			name:         "test delete proposal reconciliation",
 			title:        "this is a test proposal",
 			abstract:     "this is a test abstract",
 			proposalType: "lightning",
 			final:        true,
-			assertFunc: func(obj *talksv1.Proposal, _ *talksv1.Speaker, assertConditions []metav1.Condition) {
+			assertFunc: func(obj *talksv2.Proposal, _ *talksv1.Speaker, assertConditions []metav1.Condition) {
 				key := client.ObjectKey{Name: obj.Name, Namespace: obj.Namespace}
 				// Wait for Proposal to be Ready
 				g.Eventually(func() bool {
@@ -212,30 +441,96 @@ func Test_Proposal_Reconcile(t *testing.T) {
 			},
 		},
 		{
-			name:         "test delete Speaker, expect a proposal status change",
+			name:         "test delete final proposal with the Delete policy",
 			title:        "this is a test proposal",
 			abstract:     "this is a test abstract",
 			proposalType: "lightning",
 			final:        true,
-			assertConditions: []metav1.Condition{
-				*conditions.FalseCondition(meta.ReadyCondition, meta.FailedReason, "unable to get speaker <namespacedName>: <group> \"<name>\" not found"),
-				*conditions.TrueCondition(talksv1.FetchFailedCondition, talksv1.FetchFailedReason, "unable to get speaker <namespacedName>: <group> \"<name>\" not found"),
-			},
-			assertFunc: func(obj *talksv1.Proposal, speaker *talksv1.Speaker, assertConditions []metav1.Condition) {
-				speakerKey := client.ObjectKey{Name: speaker.Name, Namespace: speaker.Namespace}
-				// Wait for Speaker to be Ready
+			assertFunc: func(obj *talksv2.Proposal, _ *talksv1.Speaker, assertConditions []metav1.Condition) {
+				key := client.ObjectKey{Name: obj.Name, Namespace: obj.Namespace}
+				// Wait for Proposal to be Ready
 				g.Eventually(func() bool {
-					if err := testEnv.Get(ctx, speakerKey, speaker); err != nil {
+					if err := testEnv.Get(ctx, key, obj); err != nil {
 						return false
 					}
-					if !conditions.IsReady(speaker) {
+					return conditions.IsReady(obj) && obj.Status.Submission == talksv1.ProposalStateFinal
+				}, timeout).Should(BeTrue())
+
+				patchHelper, err := patch.NewHelper(obj, testEnv)
+				g.Expect(err).ToNot(HaveOccurred())
+				obj.Spec.DeletionPolicy = talksv1.DeletionPolicyDelete
+				g.Expect(patchHelper.Patch(ctx, obj)).To(Succeed())
+
+				// Delete Proposal
+				g.Expect(testEnv.Delete(ctx, obj)).To(Succeed())
+
+				// Wait for Proposal to be deleted
+				g.Eventually(func() bool {
+					if err := testEnv.Get(ctx, key, obj); err != nil {
+						return apierrors.IsNotFound(err)
+					}
+					return false
+				}, timeout).Should(BeTrue())
+
+				cfpClient, err := cfp.NewClient(os.Getenv("CFP_API_ENDPOINT"), http.DefaultClient)
+				g.Expect(err).ToNot(HaveOccurred())
+				remote, err := cfpClient.Proposals().Get(ctx, proposalID(obj))
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(remote).To(BeNil())
+			},
+		},
+		{
+			name:         "test withdraw final proposal on deletion",
+			title:        "this is a test proposal",
+			abstract:     "this is a test abstract",
+			proposalType: "lightning",
+			final:        true,
+			assertFunc: func(obj *talksv2.Proposal, _ *talksv1.Speaker, assertConditions []metav1.Condition) {
+				key := client.ObjectKey{Name: obj.Name, Namespace: obj.Namespace}
+				// Wait for Proposal to be Ready
+				g.Eventually(func() bool {
+					if err := testEnv.Get(ctx, key, obj); err != nil {
 						return false
 					}
-					readyCondition := conditions.Get(speaker, meta.ReadyCondition)
-					return speaker.Generation == readyCondition.ObservedGeneration &&
-						speaker.Generation == speaker.Status.ObservedGeneration
+					return conditions.IsReady(obj) && obj.Status.Submission == talksv1.ProposalStateFinal
+				}, timeout).Should(BeTrue())
+
+				patchHelper, err := patch.NewHelper(obj, testEnv)
+				g.Expect(err).ToNot(HaveOccurred())
+				obj.Spec.DeletionPolicy = talksv1.DeletionPolicyWithdraw
+				g.Expect(patchHelper.Patch(ctx, obj)).To(Succeed())
+
+				// Delete Proposal
+				g.Expect(testEnv.Delete(ctx, obj)).To(Succeed())
+
+				// Wait for Proposal to be deleted
+				g.Eventually(func() bool {
+					if err := testEnv.Get(ctx, key, obj); err != nil {
+						return apierrors.IsNotFound(err)
+					}
+					return false
 				}, timeout).Should(BeTrue())
 
+				cfpClient, err := cfp.NewClient(os.Getenv("CFP_API_ENDPOINT"), http.DefaultClient)
+				g.Expect(err).ToNot(HaveOccurred())
+				remote, err := cfpClient.Proposals().Get(ctx, proposalID(obj))
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(remote.Submission.Status).To(Equal(talksv1.ProposalStateWithdrawn))
+			},
+		},
+		{
+			name:         "test delete Speaker, expect the deletion to be blocked and a proposal status change",
+			title:        "this is a test proposal",
+			abstract:     "this is a test abstract",
+			proposalType: "lightning",
+			final:        true,
+			assertConditions: []metav1.Condition{
+				*conditions.TrueCondition(meta.StalledCondition, talksv1.SpeakerDeletedReason, "speaker <namespacedName> is being deleted"),
+				*conditions.FalseCondition(meta.ReadyCondition, talksv1.SpeakerDeletedReason, "speaker <namespacedName> is being deleted"),
+				*conditions.FalseCondition(talksv1.ReviewedCondition, talksv1.ReviewsPendingReason, "0 of 2 reviews"),
+			},
+			assertFunc: func(obj *talksv2.Proposal, speaker *talksv1.Speaker, assertConditions []metav1.Condition) {
+				speakerKey := client.ObjectKey{Name: speaker.Name, Namespace: speaker.Namespace}
 				key := client.ObjectKey{Name: obj.Name, Namespace: obj.Namespace}
 				// Wait for Proposal to be Ready
 				g.Eventually(func() bool {
@@ -253,32 +548,86 @@ func Test_Proposal_Reconcile(t *testing.T) {
 				// Delete Speaker
 				g.Expect(testEnv.Delete(ctx, speaker)).To(Succeed())
 
-				// Wait for <speakerto be deleted
+				// Wait for the speaker deletion to be blocked by the proposal
 				g.Eventually(func() bool {
-					if err := testEnv.Get(ctx, speakerKey, obj); err != nil {
-						return apierrors.IsNotFound(err)
+					if err := testEnv.Get(ctx, speakerKey, speaker); err != nil {
+						return false
 					}
-					return false
+					return conditions.IsTrue(speaker, talksv1.DeletionBlockedCondition)
 				}, timeout).Should(BeTrue())
+				g.Expect(conditions.GetReason(speaker, talksv1.DeletionBlockedCondition)).To(Equal(talksv1.ReferencedByProposalsReason))
+				g.Expect(conditions.GetMessage(speaker, talksv1.DeletionBlockedCondition)).To(Equal(fmt.Sprintf("speaker is referenced by proposals: %s", key.String())))
 
-				// Wait for proposal to not be Ready
+				// Wait for proposal to report the speaker deletion
 				g.Eventually(func() bool {
 					if err := testEnv.Get(ctx, key, obj); err != nil {
 						return false
 					}
-					if !conditions.IsFalse(obj, meta.ReadyCondition) {
-						return false
-					}
-					readyCondition := conditions.Get(speaker, meta.ReadyCondition)
-					return obj.Generation == readyCondition.ObservedGeneration &&
-						obj.Generation == obj.Status.ObservedGeneration
+					return conditions.GetReason(obj, meta.ReadyCondition) == talksv1.SpeakerDeletedReason
 				}, timeout).Should(BeTrue())
 				for k := range assertConditions {
-					assertConditions[k].Message = strings.ReplaceAll(assertConditions[k].Message, "<name>", obj.Spec.SpeakerRef.Name)
-					assertConditions[k].Message = strings.ReplaceAll(assertConditions[k].Message, "<namespacedName>", fmt.Sprintf("%s/%s", obj.Namespace, obj.Spec.SpeakerRef.Name))
-					assertConditions[k].Message = strings.ReplaceAll(assertConditions[k].Message, "<group>", fmt.Sprintf("Speaker.%s", talksv1.GroupVersion.Group))
+					assertConditions[k].Message = strings.ReplaceAll(assertConditions[k].Message, "<namespacedName>", speakerKey.String())
 				}
 				g.Expect(obj.Status.Conditions).To(conditions.MatchConditions(assertConditions))
+
+				// Delete the proposal, which unblocks the speaker deletion
+				g.Expect(testEnv.Delete(ctx, obj)).To(Succeed())
+				g.Eventually(func() bool {
+					if err := testEnv.Get(ctx, speakerKey, speaker); err != nil {
+						return apierrors.IsNotFound(err)
+					}
+					return false
+				}, timeout).Should(BeTrue())
+			},
+		},
+		{
+			name:         "test delete suspended Speaker, expect the deletion to be blocked",
+			title:        "this is a test proposal",
+			abstract:     "this is a test abstract",
+			proposalType: "talk",
+			assertFunc: func(obj *talksv2.Proposal, speaker *talksv1.Speaker, _ []metav1.Condition) {
+				speakerKey := client.ObjectKey{Name: speaker.Name, Namespace: speaker.Namespace}
+				key := client.ObjectKey{Name: obj.Name, Namespace: obj.Namespace}
+				// Wait for Proposal to be Ready
+				g.Eventually(func() bool {
+					if err := testEnv.Get(ctx, key, obj); err != nil {
+						return false
+					}
+					return conditions.IsReady(obj)
+				}, timeout).Should(BeTrue())
+
+				// Suspend the Speaker
+				g.Expect(testEnv.Get(ctx, speakerKey, speaker)).To(Succeed())
+				patchHelper, err := patch.NewHelper(speaker, testEnv)
+				g.Expect(err).ToNot(HaveOccurred())
+				speaker.Spec.Suspend = true
+				g.Expect(patchHelper.Patch(ctx, speaker)).To(Succeed())
+				g.Eventually(func() bool {
+					if err := testEnv.Get(ctx, speakerKey, speaker); err != nil {
+						return false
+					}
+					return conditions.GetReason(speaker, meta.ReadyCondition) == meta.SuspendedReason
+				}, timeout).Should(BeTrue())
+
+				// Delete the suspended Speaker, whose deletion is still blocked
+				// by the proposal
+				g.Expect(testEnv.Delete(ctx, speaker)).To(Succeed())
+				g.Eventually(func() bool {
+					if err := testEnv.Get(ctx, speakerKey, speaker); err != nil {
+						return false
+					}
+					return conditions.IsTrue(speaker, talksv1.DeletionBlockedCondition)
+				}, timeout).Should(BeTrue())
+				g.Expect(conditions.GetMessage(speaker, talksv1.DeletionBlockedCondition)).To(Equal(fmt.Sprintf("speaker is referenced by proposals: %s", key.String())))
+
+				// Delete the proposal, which unblocks the speaker deletion
+				g.Expect(testEnv.Delete(ctx, obj)).To(Succeed())
+				g.Eventually(func() bool {
+					if err := testEnv.Get(ctx, speakerKey, speaker); err != nil {
+						return apierrors.IsNotFound(err)
+					}
+					return false
+				}, timeout).Should(BeTrue())
 			},
 		},
 	}
@@ -320,18 +669,18 @@ func Test_Proposal_Reconcile(t *testing.T) {
 					speaker.Generation == speaker.Status.ObservedGeneration
 			}, timeout).Should(BeTrue())
 
-			obj := &talksv1.Proposal{
+			obj := &talksv2.Proposal{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "proposal",
 					Namespace: ns.Name,
 				},
-				Spec: talksv1.ProposalSpec{
+				Spec: talksv2.ProposalSpec{
 					Title:    tc.title,
 					Abstract: tc.abstract,
 					Type:     tc.proposalType,
 					Final:    tc.final,
-					SpeakerRef: &talksv1.SpeakerRef{
-						Name: speaker.Name,
+					SpeakerRefs: []talksv2.SpeakerRef{
+						{Name: speaker.Name},
 					},
 				},
 			}
@@ -347,3 +696,249 @@ func Test_Proposal_Reconcile(t *testing.T) {
 		})
 	}
 }
+
+func Test_Proposal_RequestsForSpeakerChange(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	nsA, err := testEnv.CreateNamespace(ctx, "speaker-ns")
+	g.Expect(err).NotTo(HaveOccurred())
+	defer func() {
+		g.Expect(testEnv.Delete(ctx, nsA)).To(Succeed())
+	}()
+	nsB, err := testEnv.CreateNamespace(ctx, "speaker-ns")
+	g.Expect(err).NotTo(HaveOccurred())
+	defer func() {
+		g.Expect(testEnv.Delete(ctx, nsB)).To(Succeed())
+	}()
+
+	// Speakers with the same name in both namespaces
+	speakerA := &talksv1.Speaker{
+		ObjectMeta: metav1.ObjectMeta{Name: "alice", Namespace: nsA.Name},
+		Spec:       talksv1.SpeakerSpec{Name: "alice", Bio: "alice from a", Email: fmt.Sprintf("alice@%s.dev", nsA.Name)},
+	}
+	speakerB := &talksv1.Speaker{
+		ObjectMeta: metav1.ObjectMeta{Name: "alice", Namespace: nsB.Name},
+		Spec:       talksv1.SpeakerSpec{Name: "alice", Bio: "alice from b", Email: fmt.Sprintf("alice@%s.dev", nsB.Name)},
+	}
+	g.Expect(testEnv.CreateAndWait(ctx, speakerA)).To(Succeed())
+	g.Expect(testEnv.CreateAndWait(ctx, speakerB)).To(Succeed())
+
+	newProposal := func(namespace, name string, ref talksv2.SpeakerRef) *talksv2.Proposal {
+		return &talksv2.Proposal{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: talksv2.ProposalSpec{
+				Title:       "this is a test proposal",
+				Abstract:    "this is a test abstract",
+				Type:        "lightning",
+				SpeakerRefs: []talksv2.SpeakerRef{ref},
+			},
+		}
+	}
+	proposals := []*talksv2.Proposal{
+		newProposal(nsA.Name, "local", talksv2.SpeakerRef{Name: "alice"}),
+		newProposal(nsB.Name, "same-name", talksv2.SpeakerRef{Name: "alice"}),
+		newProposal(nsB.Name, "cross-namespace", talksv2.SpeakerRef{Name: "alice", Namespace: nsA.Name}),
+	}
+	for _, p := range proposals {
+		g.Expect(testEnv.CreateAndWait(ctx, p)).To(Succeed())
+	}
+
+	r := &ProposalReconciler{Client: testEnv.Client}
+
+	testCases := []struct {
+		name    string
+		speaker *talksv1.Speaker
+		want    []client.ObjectKey
+	}{
+		{
+			name:    "test speaker requeues the proposals of its namespace and the ones referencing it explicitly",
+			speaker: speakerA,
+			want: []client.ObjectKey{
+				{Namespace: nsA.Name, Name: "local"},
+				{Namespace: nsB.Name, Name: "cross-namespace"},
+			},
+		},
+		{
+			name:    "test speaker with the same name does not requeue the proposals of another namespace",
+			speaker: speakerB,
+			want: []client.ObjectKey{
+				{Namespace: nsB.Name, Name: "same-name"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			speaker := tc.speaker.DeepCopy()
+			speaker.Status.ID = fmt.Sprintf("%s-%s", speaker.Namespace, speaker.Name)
+
+			g.Eventually(func() []client.ObjectKey {
+				var keys []client.ObjectKey
+				for _, req := range r.requestsForSpeakerChange(speaker) {
+					keys = append(keys, req.NamespacedName)
+				}
+				return keys
+			}, timeout).Should(ConsistOf(tc.want))
+		})
+	}
+}
+
+func Test_Proposal_CrossNamespaceSpeaker(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	speakerNS, err := testEnv.CreateNamespace(ctx, "speaker-ns")
+	g.Expect(err).NotTo(HaveOccurred())
+	defer func() {
+		g.Expect(testEnv.Delete(ctx, speakerNS)).To(Succeed())
+	}()
+	proposalNS, err := testEnv.CreateNamespace(ctx, "proposal-ns")
+	g.Expect(err).NotTo(HaveOccurred())
+	defer func() {
+		g.Expect(testEnv.Delete(ctx, proposalNS)).To(Succeed())
+	}()
+
+	speaker := &talksv1.Speaker{
+		ObjectMeta: metav1.ObjectMeta{Name: "speaker", Namespace: speakerNS.Name},
+		Spec: talksv1.SpeakerSpec{
+			Name:  "test",
+			Bio:   "test speaker",
+			Email: fmt.Sprintf("speaker@%s.dev", speakerNS.Name),
+		},
+	}
+	g.Expect(testEnv.CreateAndWait(ctx, speaker)).To(Succeed())
+
+	speakerKey := client.ObjectKeyFromObject(speaker)
+	// Wait for Speaker to be Ready
+	g.Eventually(func() bool {
+		if err := testEnv.Get(ctx, speakerKey, speaker); err != nil {
+			return false
+		}
+		return conditions.IsReady(speaker) && speaker.Status.ID != ""
+	}, timeout).Should(BeTrue())
+
+	obj := &talksv2.Proposal{
+		ObjectMeta: metav1.ObjectMeta{Name: "proposal", Namespace: proposalNS.Name},
+		Spec: talksv2.ProposalSpec{
+			Title:    "this is a test proposal",
+			Abstract: "this is a test abstract",
+			Type:     "lightning",
+			SpeakerRefs: []talksv2.SpeakerRef{
+				{Name: speaker.Name, Namespace: speaker.Namespace},
+			},
+		},
+	}
+	g.Expect(testEnv.CreateAndWait(ctx, obj)).To(Succeed())
+
+	key := client.ObjectKeyFromObject(obj)
+	// Wait for Proposal to be Stalled, the speaker does not allow access
+	// from other namespaces
+	g.Eventually(func() bool {
+		if err := testEnv.Get(ctx, key, obj); err != nil {
+			return false
+		}
+		return conditions.IsStalled(obj) && obj.Generation == obj.Status.ObservedGeneration
+	}, timeout).Should(BeTrue())
+
+	message := fmt.Sprintf("speaker '%s/%s' can't be accessed due to missing ACL labels on 'accessFrom'", speaker.Namespace, speaker.Name)
+	g.Expect(conditions.GetReason(obj, meta.StalledCondition)).To(Equal(aclapi.AccessDeniedReason))
+	g.Expect(conditions.GetMessage(obj, meta.ReadyCondition)).To(Equal(message))
+	g.Expect(obj.Status.Submission).To(BeEmpty())
+
+	// Cross-namespace references are denied regardless of the ACL when disabled
+	r := &ProposalReconciler{Client: testEnv.Client, NoCrossNamespaceRefs: true}
+	err = r.checkSpeakerAccess(ctx, obj, speaker)
+	var stallErr *stallingError
+	g.Expect(errors.As(err, &stallErr)).To(BeTrue())
+	g.Expect(stallErr.Reason).To(Equal(aclapi.AccessDeniedReason))
+
+	// Allow access from the namespace of the proposal
+	patchHelper, err := patch.NewHelper(speaker, testEnv.Client)
+	g.Expect(err).NotTo(HaveOccurred())
+	speaker.Spec.AccessFrom = &aclapi.AccessFrom{
+		NamespaceSelectors: []aclapi.NamespaceSelector{
+			{MatchLabels: map[string]string{"kubernetes.io/metadata.name": proposalNS.Name}},
+		},
+	}
+	g.Expect(patchHelper.Patch(ctx, speaker)).To(Succeed())
+
+	// Wait for Proposal to be Ready
+	g.Eventually(func() bool {
+		if err := testEnv.Get(ctx, key, obj); err != nil {
+			return false
+		}
+		return conditions.IsReady(obj)
+	}, timeout).Should(BeTrue())
+	g.Expect(conditions.Has(obj, meta.StalledCondition)).To(BeFalse())
+	g.Expect(obj.Status.Submission).To(Equal(talksv1.ProposalStateDraft))
+}
+
+func Test_Proposal_AdoptFinalRecord(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	ns, err := testEnv.CreateNamespace(ctx, "proposal-ns")
+	g.Expect(err).NotTo(HaveOccurred())
+	defer func() {
+		g.Expect(testEnv.Delete(ctx, ns)).To(Succeed())
+	}()
+
+	speaker := &talksv1.Speaker{
+		ObjectMeta: metav1.ObjectMeta{Name: "speaker", Namespace: ns.Name},
+		Spec: talksv1.SpeakerSpec{
+			Name:  "test",
+			Bio:   "test speaker",
+			Email: fmt.Sprintf("speaker@%s.dev", ns.Name),
+		},
+	}
+	g.Expect(testEnv.CreateAndWait(ctx, speaker)).To(Succeed())
+
+	speakerKey := client.ObjectKeyFromObject(speaker)
+	// Wait for Speaker to be Ready
+	g.Eventually(func() bool {
+		if err := testEnv.Get(ctx, speakerKey, speaker); err != nil {
+			return false
+		}
+		return conditions.IsReady(speaker) && speaker.Status.ID != ""
+	}, timeout).Should(BeTrue())
+
+	// Create a suspended draft Proposal, so that its record can be created as
+	// final as if its status had been lost after it was submitted
+	obj := &talksv2.Proposal{
+		ObjectMeta: metav1.ObjectMeta{Name: "proposal", Namespace: ns.Name},
+		Spec: talksv2.ProposalSpec{
+			Title:       "this is a test proposal",
+			Abstract:    "this is a test abstract",
+			Type:        "lightning",
+			SpeakerRefs: []talksv2.SpeakerRef{{Name: speaker.Name}},
+			Suspend:     true,
+		},
+	}
+	g.Expect(testEnv.CreateAndWait(ctx, obj)).To(Succeed())
+
+	cfpClient, err := cfp.NewClient(os.Getenv("CFP_API_ENDPOINT"), http.DefaultClient)
+	g.Expect(err).ToNot(HaveOccurred())
+	record := proposalRecord(obj, []string{speaker.Status.ID}, talksv1.ProposalStateFinal, ownerMarker("test-cluster", obj))
+	_, err = cfpClient.Proposals().Create(ctx, record)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Resume the reconciliation
+	key := client.ObjectKeyFromObject(obj)
+	g.Expect(testEnv.Get(ctx, key, obj)).To(Succeed())
+	patchHelper, err := patch.NewHelper(obj, testEnv)
+	g.Expect(err).ToNot(HaveOccurred())
+	obj.Spec.Suspend = false
+	g.Expect(patchHelper.Patch(ctx, obj)).To(Succeed())
+
+	// Wait for Proposal to be Ready, its own record is adopted
+	g.Eventually(func() bool {
+		if err := testEnv.Get(ctx, key, obj); err != nil {
+			return false
+		}
+		return conditions.IsReady(obj) && obj.Generation == obj.Status.ObservedGeneration
+	}, timeout).Should(BeTrue())
+	g.Expect(obj.Status.Submission).To(Equal(talksv1.ProposalStateFinal))
+
+	// The adopted record is still final
+	remote, err := cfpClient.Proposals().Get(ctx, proposalID(obj))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(remote.Submission.Status).To(Equal(talksv1.ProposalStateFinal))
+}
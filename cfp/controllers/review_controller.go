@@ -0,0 +1,480 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	"github.com/fluxcd/pkg/runtime/patch"
+	cfptypes "github.com/scottrigby/cfp-api/pkg/types"
+
+	talksv1 "github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/api/v1"
+	talksv2 "github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/api/v2"
+	"github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/internal/cfp"
+)
+
+var reviewOwnedConditions = []string{
+	meta.ReadyCondition,
+	meta.ReconcilingCondition,
+	meta.StalledCondition,
+	talksv1.CreateFailedCondition,
+	talksv1.UpdateFailedCondition,
+	talksv1.FetchFailedCondition,
+}
+
+// ReviewReconciler reconciles a Review object
+type ReviewReconciler struct {
+	client.Client
+	HTTPClient     *http.Client
+	EventRecorder  record.EventRecorder
+	ControllerName string
+	CfpAPI         string
+
+	// AllowedEndpoints are the addresses of the CFP APIs other than CfpAPI
+	// that Conferences can collect the reviews of.
+	AllowedEndpoints []string
+
+	// RateLimiter limits the requests sent to the CFP API, it is shared by
+	// the reconcilers.
+	RateLimiter *cfp.RateLimiter
+
+	// Credentials authenticate the requests sent to the CFP API, if set.
+	Credentials *cfp.CredentialStore
+}
+
+//+kubebuilder:rbac:groups=talks.kubecon.na,resources=reviews,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=talks.kubecon.na,resources=reviews/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=talks.kubecon.na,resources=reviews/finalizers,verbs=update
+//+kubebuilder:rbac:groups=talks.kubecon.na,resources=proposals,verbs=get;list;watch
+//+kubebuilder:rbac:groups=talks.kubecon.na,resources=conferences,verbs=get;list;watch
+
+// Reconcile pushes the score of a Review to the reviews of its Proposal in
+// the CFP API. Reviews are aggregated into the status of their Proposal by
+// the ProposalReconciler.
+func (r *ReviewReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, retErr error) {
+	ctx, span := startReconcileSpan(ctx, "Review", req)
+	defer func() { endSpan(span, retErr) }()
+
+	log := log.FromContext(ctx)
+	// Fetch the Review instance
+	// Automatically requeue if an error is returned
+	// otherwise requeue based on the result.requeue and result.requeueAfter
+	obj := &talksv1.Review{}
+	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// A stalled object fails the same way until its spec changes, it is not
+	// reconciled again on resync or on the events of other objects
+	if skipStalled(obj, obj.Status.ObservedGeneration) {
+		return ctrl.Result{}, nil
+	}
+
+	log.Info("reconciling review", "review", obj.Name)
+
+	// Initialize the patch helper with the current version of the object.
+	// Helper is a utility for ensuring the proper patching of objects.
+	patchHelper, err := patch.NewHelper(obj, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Always attempt to Patch the Review object and status after each reconciliation.
+	defer func() {
+		// Patch the object, ignoring conflicts on the conditions owned by this controller
+		patchOpts := []patch.Option{
+			patch.WithOwnedConditions{
+				Conditions: reviewOwnedConditions,
+			},
+		}
+
+		patchOpts = append(patchOpts, patch.WithFieldOwner(r.ControllerName))
+
+		// Set status observed generation field if the object is stalled, or ready.
+		if conditions.IsStalled(obj) || conditions.IsReady(obj) {
+			patchOpts = append(patchOpts, patch.WithStatusObservedGeneration{})
+		}
+
+		// Finally, patch the resource
+		if err := patchHelper.Patch(ctx, obj, patchOpts...); err != nil {
+			if !obj.GetDeletionTimestamp().IsZero() {
+				err = kerrors.FilterOut(err, func(e error) bool { return apierrors.IsNotFound(e) })
+			}
+
+			retErr = kerrors.NewAggregate([]error{retErr, err})
+		}
+	}()
+
+	// Set a finalizer on the obj object if not set
+	if !controllerutil.ContainsFinalizer(obj, talksv1.Finalizer) {
+		controllerutil.AddFinalizer(obj, talksv1.Finalizer)
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	// Get the address of the CFP API holding the record of the review.
+	// Only the allowed endpoints are sent requests: a review being deleted
+	// keeps its finalizer until the endpoint of its record is allowed again.
+	_, endpoint, err := r.resolveEndpoint(ctx, obj)
+	if err != nil {
+		var stallErr *stallingError
+		if errors.As(err, &stallErr) {
+			conditions.MarkStalled(obj, stallErr.Reason, err.Error())
+			conditions.MarkFalse(obj, meta.ReadyCondition, stallErr.Reason, err.Error())
+			r.EventRecorder.Event(obj, corev1.EventTypeWarning, stallErr.Reason, err.Error())
+			return ctrl.Result{}, nil
+		}
+		conditions.MarkTrue(obj, talksv1.FetchFailedCondition, talksv1.FetchFailedReason, err.Error())
+		conditions.MarkFalse(obj, meta.ReadyCondition, meta.FailedReason, err.Error())
+		r.EventRecorder.Event(obj, corev1.EventTypeWarning, talksv1.FetchFailedReason, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	//create a new cfp client
+	cfpClient, err := cfp.NewClient(endpoint, r.HTTPClient)
+	if err != nil {
+		conditions.MarkStalled(obj, talksv1.CreateFailedCondition, "Failed to create cfp client")
+		return ctrl.Result{}, err
+	}
+	cfpClient.Limiter = r.RateLimiter
+	cfpClient.Credentials = r.Credentials
+
+	// Check if this a deletion, if yes api call to delete the Review
+	if !obj.ObjectMeta.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(ctx, obj, cfpClient)
+	}
+
+	// Perform reconciliation logic
+	result, retErr = r.reconcile(ctx, obj, cfpClient)
+	return
+}
+
+// reconcile will perform the reconciliation logic for the Review object
+// While reconciling if an error is encountered, it sets the failure details in the appropriate
+// status condition and returns the error.
+func (r *ReviewReconciler) reconcile(ctx context.Context, obj *talksv1.Review, client *cfp.Client) (result ctrl.Result, retErr error) {
+	// defer func attempt to set the Ready condition and unset all needed conditions based on the reconciliation
+	defer func() {
+		// A stalled reconciliation is not retried until the object changes,
+		// a waiting one is retried once the wait is over.
+		retErr = classifyError(retErr)
+		var (
+			stallErr *stallingError
+			waitErr  *waitingError
+		)
+		switch {
+		case errors.As(retErr, &stallErr):
+			conditions.MarkStalled(obj, stallErr.Reason, stallErr.Error())
+			conditions.MarkFalse(obj, meta.ReadyCondition, stallErr.Reason, stallErr.Error())
+			r.EventRecorder.Event(obj, corev1.EventTypeWarning, stallErr.Reason, stallErr.Error())
+			result, retErr = ctrl.Result{}, nil
+			return
+		case errors.As(retErr, &waitErr):
+			conditions.Delete(obj, meta.StalledCondition)
+			conditions.MarkFalse(obj, meta.ReadyCondition, waitErr.Reason, waitErr.Error())
+			r.EventRecorder.Event(obj, corev1.EventTypeNormal, waitErr.Reason, waitErr.Error())
+			result, retErr = ctrl.Result{RequeueAfter: waitErr.RequeueAfter}, nil
+			return
+		}
+
+		if !result.Requeue && retErr == nil {
+			conditions.Delete(obj, meta.ReconcilingCondition)
+			conditions.Delete(obj, meta.StalledCondition)
+			conditions.Delete(obj, talksv1.CreateFailedCondition)
+			conditions.Delete(obj, talksv1.UpdateFailedCondition)
+			conditions.Delete(obj, talksv1.FetchFailedCondition)
+			conditions.MarkTrue(obj, meta.ReadyCondition, meta.SucceededReason, "reconciled '%s' successfully", obj.Name)
+		}
+
+		if retErr != nil {
+			var apiErr *cfp.Error
+			if ok := errors.As(retErr, &apiErr); ok {
+				switch apiErr.Reason {
+				case cfp.ErrCreateReview:
+					conditions.MarkTrue(obj, talksv1.CreateFailedCondition, apiErrorReason(apiErr), apiErr.Error())
+					conditions.MarkFalse(obj, meta.ReadyCondition, apiErrorReason(apiErr), apiErr.Error())
+				case cfp.ErrUpdateReview:
+					conditions.MarkTrue(obj, talksv1.UpdateFailedCondition, apiErrorReason(apiErr), apiErr.Error())
+					conditions.MarkFalse(obj, meta.ReadyCondition, apiErrorReason(apiErr), apiErr.Error())
+				case cfp.ErrCreateRequest, cfp.ErrMakeRequest, cfp.ErrFetchReview:
+					conditions.MarkFalse(obj, meta.ReadyCondition, apiErrorReason(apiErr), apiErr.Error())
+				default:
+					conditions.MarkFalse(obj, meta.ReadyCondition, meta.FailedReason, apiErr.Error())
+				}
+				r.EventRecorder.Event(obj, corev1.EventTypeWarning, conditions.GetReason(obj, meta.ReadyCondition), apiErr.Error())
+			}
+		}
+	}()
+
+	// Set the initial status of the object to be reconciling
+	if obj.Generation != obj.Status.ObservedGeneration {
+		conditions.MarkReconciling(obj, meta.ProgressingReason, fmt.Sprintf("Reconciling a new generation of the object %d", obj.Generation))
+	}
+
+	// Resolve the ID of the reviewed proposal
+	proposalID, err := r.getProposalID(ctx, obj)
+	if err != nil {
+		conditions.MarkTrue(obj, talksv1.FetchFailedCondition, talksv1.FetchFailedReason, err.Error())
+		conditions.MarkFalse(obj, meta.ReadyCondition, meta.FailedReason, err.Error())
+		r.EventRecorder.Event(obj, corev1.EventTypeWarning, talksv1.FetchFailedReason, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	// Move the review if it has been pushed to another proposal, or to the
+	// CFP API of another conference
+	if obj.Status.ID != "" && (obj.Status.ProposalID != proposalID || !sameEndpoint(reviewEndpoint(obj, client), client.Endpoint())) {
+		previous, err := r.newClient(reviewEndpoint(obj, client))
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := previous.Reviews(obj.Status.ProposalID).Delete(ctx, obj.Status.ID); err != nil && !cfp.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		r.EventRecorder.Eventf(obj, corev1.EventTypeNormal, reviewDeletedReason, "deleted review '%s' from proposal '%s' in the CFP API", obj.Status.ID, obj.Status.ProposalID)
+		obj.Status.ID = ""
+		obj.Status.ProposalID = ""
+		obj.Status.Endpoint = ""
+	}
+
+	reviews := client.Reviews(proposalID)
+	review := reviewRecord(obj, proposalID)
+
+	// Update the review if it has already been pushed, reading and comparing
+	// it again if it is modified before the update
+	if obj.Status.ID != "" {
+		var updated bool
+		err := retry.OnError(retry.DefaultRetry, cfp.IsPreconditionFailed, func() error {
+			remote, err := reviews.Get(ctx, obj.Status.ID)
+			if err != nil {
+				return err
+			}
+
+			// Check if the fields we own are the same
+			// if not, it means we have changed the spec, so we need to update the review
+			if len(driftedFields(ownedReviewFields(obj), reviewRecordFields(remote))) == 0 {
+				return nil
+			}
+			review.ETag = remote.ETag
+			_, err = reviews.Update(ctx, review)
+			updated = err == nil
+			return err
+		})
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		obj.Status.Endpoint = client.Endpoint()
+		if updated {
+			r.EventRecorder.Eventf(obj, corev1.EventTypeNormal, reviewUpdatedReason, "updated review '%s' of proposal '%s' in the CFP API", obj.Status.ID, proposalID)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// Create the Review
+	if _, err := reviews.Create(ctx, review); err != nil {
+		return ctrl.Result{}, err
+	}
+	r.EventRecorder.Eventf(obj, corev1.EventTypeNormal, reviewCreatedReason, "created review '%s-%s' of proposal '%s' in the CFP API", obj.Namespace, obj.Name, proposalID)
+
+	// Set the IDs in the status
+	obj.Status.ID = fmt.Sprintf("%s-%s", obj.Namespace, obj.Name)
+	obj.Status.ProposalID = proposalID
+	obj.Status.Endpoint = client.Endpoint()
+	return ctrl.Result{}, nil
+}
+
+// reviewEndpoint returns the address of the CFP API holding the record of the
+// review, the records created before it was recorded in the status are held
+// by the CFP API of the given client.
+func reviewEndpoint(obj *talksv1.Review, client *cfp.Client) string {
+	if obj.Status.Endpoint == "" {
+		return client.Endpoint()
+	}
+	return obj.Status.Endpoint
+}
+
+// newClient returns a client of the CFP API at the given endpoint, which must
+// be the default endpoint or one of the allowed endpoints.
+func (r *ReviewReconciler) newClient(endpoint string) (*cfp.Client, error) {
+	endpoint, err := recordedEndpoint(endpoint, r.CfpAPI, r.AllowedEndpoints)
+	if err != nil {
+		return nil, err
+	}
+	client, err := cfp.NewClient(endpoint, r.HTTPClient)
+	if err != nil {
+		return nil, err
+	}
+	client.Limiter = r.RateLimiter
+	client.Credentials = r.Credentials
+	return client, nil
+}
+
+// getProposalID returns the CFP API ID of the Proposal referenced by the
+// Review. It fails if the proposal does not exist or has not been submitted
+// to the CFP API yet.
+func (r *ReviewReconciler) getProposalID(ctx context.Context, obj *talksv1.Review) (string, error) {
+	proposal := &talksv2.Proposal{}
+	namespacedName := proposalKey(obj)
+	if err := r.Get(ctx, namespacedName, proposal); err != nil {
+		return "", fmt.Errorf("unable to get proposal %s: %w", namespacedName.String(), err)
+	}
+
+	if proposal.Status.Submission == "" {
+		return "", fmt.Errorf("proposal %s has not been submitted yet", namespacedName.String())
+	}
+
+	return proposalID(proposal), nil
+}
+
+// resolveEndpoint returns the conference of the Review, if any, and the
+// address of the CFP API holding its record. The record of a Review being
+// deleted is deleted from the CFP API recorded in its status, rather than from
+// the one of its conference which may be gone or have changed.
+func (r *ReviewReconciler) resolveEndpoint(ctx context.Context, obj *talksv1.Review) (*talksv1.Conference, string, error) {
+	if !obj.ObjectMeta.DeletionTimestamp.IsZero() {
+		switch {
+		case obj.Status.Endpoint != "":
+			endpoint, err := recordedEndpoint(obj.Status.Endpoint, r.CfpAPI, r.AllowedEndpoints)
+			return nil, endpoint, err
+		case obj.Status.ID == "":
+			// There is no record to delete from the CFP API
+			return nil, r.CfpAPI, nil
+		}
+	}
+
+	conference, err := r.getConference(ctx, obj)
+	if err != nil {
+		return nil, "", err
+	}
+	endpoint, err := cfpEndpoint(conference, r.CfpAPI, r.AllowedEndpoints)
+	return conference, endpoint, err
+}
+
+// getConference returns the conference the Proposal referenced by the Review
+// is submitted to, or nil if it is not submitted to any.
+func (r *ReviewReconciler) getConference(ctx context.Context, obj *talksv1.Review) (*talksv1.Conference, error) {
+	proposal := &talksv2.Proposal{}
+	namespacedName := proposalKey(obj)
+	if err := r.Get(ctx, namespacedName, proposal); err != nil {
+		return nil, fmt.Errorf("unable to get proposal %s: %w", namespacedName.String(), err)
+	}
+	if proposal.Spec.ConferenceRef == nil {
+		return nil, nil
+	}
+
+	conference := &talksv1.Conference{}
+	namespacedName = conferenceKey(proposal)
+	if err := r.Get(ctx, namespacedName, conference); err != nil {
+		return nil, fmt.Errorf("unable to get conference %s: %w", namespacedName.String(), err)
+	}
+	return conference, nil
+}
+
+// reconcileDelete will delete the obj from the CFP API
+func (r *ReviewReconciler) reconcileDelete(ctx context.Context, obj *talksv1.Review, client *cfp.Client) (ctrl.Result, error) {
+	// api call to delete the Review if necessary
+	if obj.Status.ID != "" {
+		// A record which is not found has already been deleted, along with
+		// its proposal
+		err := client.Reviews(obj.Status.ProposalID).Delete(ctx, obj.Status.ID)
+		if err != nil && !cfp.IsNotFound(err) {
+			r.EventRecorder.Event(obj, corev1.EventTypeWarning, failureReason(err), err.Error())
+			// return the error so we can requeue
+			return ctrl.Result{}, err
+		}
+		r.EventRecorder.Eventf(obj, corev1.EventTypeNormal, reviewDeletedReason, "deleted review '%s' from proposal '%s' in the CFP API", obj.Status.ID, obj.Status.ProposalID)
+	}
+	// clean the finalizer
+	controllerutil.RemoveFinalizer(obj, talksv1.Finalizer)
+
+	// Stop the reconciliation
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager, with the default
+// options.
+func (r *ReviewReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return r.SetupWithManagerAndOptions(mgr, ReconcilerOptions{})
+}
+
+// SetupWithManagerAndOptions sets up the controller with the Manager and the
+// given options.
+func (r *ReviewReconciler) SetupWithManagerAndOptions(mgr ctrl.Manager, opts ReconcilerOptions) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&talksv1.Review{}).
+		WithOptions(opts.controllerOptions()).
+		Complete(r)
+}
+
+// proposalKey returns the namespace/name of the proposal referenced by the
+// Review, which defaults to the namespace of the Review.
+func proposalKey(obj *talksv1.Review) types.NamespacedName {
+	namespacedName := types.NamespacedName{Namespace: obj.Namespace, Name: obj.Spec.ProposalRef.Name}
+	if obj.Spec.ProposalRef.Namespace != "" {
+		namespacedName.Namespace = obj.Spec.ProposalRef.Namespace
+	}
+	return namespacedName
+}
+
+// reviewFields are the fields of a CFP API review record owned by a Review.
+type reviewFields struct {
+	Reviewer string `json:"reviewer"`
+	Score    int    `json:"score"`
+	Comments string `json:"comments"`
+}
+
+func ownedReviewFields(obj *talksv1.Review) reviewFields {
+	return reviewFields{
+		Reviewer: obj.Spec.Reviewer,
+		Score:    obj.Spec.Score,
+		Comments: obj.Spec.Comments,
+	}
+}
+
+func reviewRecordFields(r *cfptypes.Review) reviewFields {
+	return reviewFields{
+		Reviewer: r.Reviewer,
+		Score:    r.Score,
+		Comments: r.Comments,
+	}
+}
+
+// reviewRecord returns the CFP API record of the Review of the proposal with
+// the given ID.
+func reviewRecord(obj *talksv1.Review, proposalID string) *cfptypes.Review {
+	return &cfptypes.Review{
+		ID:         fmt.Sprintf("%s-%s", obj.Namespace, obj.Name),
+		ProposalID: proposalID,
+		Reviewer:   obj.Spec.Reviewer,
+		Score:      obj.Spec.Score,
+		Comments:   obj.Spec.Comments,
+	}
+}
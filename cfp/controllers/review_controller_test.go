@@ -0,0 +1,285 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	. "github.com/onsi/gomega"
+	talksv1 "github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/api/v1"
+	talksv2 "github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/api/v2"
+)
+
+func Test_Review_Reconcile(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	testCases := []struct {
+		name             string
+		scores           []int
+		assertConditions []metav1.Condition
+		assertFunc       func(obj *talksv2.Proposal, reviews []*talksv1.Review, assertConditions []metav1.Condition)
+	}{
+		{
+			name:   "test reviews aggregation with pending reviews",
+			scores: []int{4},
+			assertConditions: []metav1.Condition{
+				*conditions.TrueCondition(meta.ReadyCondition, meta.SucceededReason, "reconciled '<name>' successfully"),
+				*conditions.FalseCondition(talksv1.ReviewedCondition, talksv1.ReviewsPendingReason, "1 of 2 reviews"),
+			},
+			assertFunc: func(obj *talksv2.Proposal, _ []*talksv1.Review, assertConditions []metav1.Condition) {
+				key := client.ObjectKey{Name: obj.Name, Namespace: obj.Namespace}
+				// Wait for the review to be aggregated
+				g.Eventually(func() bool {
+					if err := testEnv.Get(ctx, key, obj); err != nil {
+						return false
+					}
+					return obj.Status.ReviewCount == 1
+				}, timeout).Should(BeTrue())
+
+				g.Expect(obj.Status.MeanScore).To(Equal("4.00"))
+				g.Expect(obj.Status.DecisionReady).To(BeFalse())
+
+				for k := range assertConditions {
+					assertConditions[k].Message = strings.ReplaceAll(assertConditions[k].Message, "<name>", obj.Name)
+				}
+				g.Expect(obj.Status.Conditions).To(conditions.MatchConditions(assertConditions))
+			},
+		},
+		{
+			name:   "test reviews aggregation with enough reviews",
+			scores: []int{4, 5},
+			assertConditions: []metav1.Condition{
+				*conditions.TrueCondition(meta.ReadyCondition, meta.SucceededReason, "reconciled '<name>' successfully"),
+				*conditions.TrueCondition(talksv1.ReviewedCondition, talksv1.DecisionReadyReason, "2 reviews with a mean score of 4.50"),
+			},
+			assertFunc: func(obj *talksv2.Proposal, _ []*talksv1.Review, assertConditions []metav1.Condition) {
+				key := client.ObjectKey{Name: obj.Name, Namespace: obj.Namespace}
+				// Wait for the proposal to be ready for a decision
+				g.Eventually(func() bool {
+					if err := testEnv.Get(ctx, key, obj); err != nil {
+						return false
+					}
+					return obj.Status.DecisionReady
+				}, timeout).Should(BeTrue())
+
+				g.Expect(obj.Status.ReviewCount).To(Equal(2))
+				g.Expect(obj.Status.MeanScore).To(Equal("4.50"))
+
+				for k := range assertConditions {
+					assertConditions[k].Message = strings.ReplaceAll(assertConditions[k].Message, "<name>", obj.Name)
+				}
+				g.Expect(obj.Status.Conditions).To(conditions.MatchConditions(assertConditions))
+			},
+		},
+		{
+			name:   "test delete review, expect the aggregation to be updated",
+			scores: []int{2, 5},
+			assertConditions: []metav1.Condition{
+				*conditions.TrueCondition(meta.ReadyCondition, meta.SucceededReason, "reconciled '<name>' successfully"),
+				*conditions.FalseCondition(talksv1.ReviewedCondition, talksv1.ReviewsPendingReason, "1 of 2 reviews"),
+			},
+			assertFunc: func(obj *talksv2.Proposal, reviews []*talksv1.Review, assertConditions []metav1.Condition) {
+				key := client.ObjectKey{Name: obj.Name, Namespace: obj.Namespace}
+				// Wait for the proposal to be ready for a decision
+				g.Eventually(func() bool {
+					if err := testEnv.Get(ctx, key, obj); err != nil {
+						return false
+					}
+					return obj.Status.DecisionReady
+				}, timeout).Should(BeTrue())
+
+				// Delete the first review
+				g.Expect(testEnv.Delete(ctx, reviews[0])).To(Succeed())
+
+				// Wait for Review to be deleted
+				reviewKey := client.ObjectKey{Name: reviews[0].Name, Namespace: reviews[0].Namespace}
+				g.Eventually(func() bool {
+					if err := testEnv.Get(ctx, reviewKey, reviews[0]); err != nil {
+						return apierrors.IsNotFound(err)
+					}
+					return false
+				}, timeout).Should(BeTrue())
+
+				// Wait for the deletion to be aggregated
+				g.Eventually(func() bool {
+					if err := testEnv.Get(ctx, key, obj); err != nil {
+						return false
+					}
+					return obj.Status.ReviewCount == 1
+				}, timeout).Should(BeTrue())
+
+				g.Expect(obj.Status.MeanScore).To(Equal("5.00"))
+				g.Expect(obj.Status.DecisionReady).To(BeFalse())
+
+				for k := range assertConditions {
+					assertConditions[k].Message = strings.ReplaceAll(assertConditions[k].Message, "<name>", obj.Name)
+				}
+				g.Expect(obj.Status.Conditions).To(conditions.MatchConditions(assertConditions))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ns, err := testEnv.CreateNamespace(ctx, "review-ns")
+			g.Expect(err).NotTo(HaveOccurred())
+			defer func() {
+				g.Expect(testEnv.Delete(ctx, ns)).To(Succeed())
+			}()
+
+			speaker := &talksv1.Speaker{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "speaker",
+					Namespace: ns.Name,
+				},
+				Spec: talksv1.SpeakerSpec{
+					Name:  "test",
+					Bio:   "test speaker",
+					Email: "test.speaker@gmail.com",
+				},
+			}
+			g.Expect(testEnv.CreateAndWait(ctx, speaker)).To(Succeed())
+
+			// Wait for Speaker to be Ready
+			speakerKey := client.ObjectKey{Name: speaker.Name, Namespace: speaker.Namespace}
+			g.Eventually(func() bool {
+				if err := testEnv.Get(ctx, speakerKey, speaker); err != nil {
+					return false
+				}
+				return conditions.IsReady(speaker)
+			}, timeout).Should(BeTrue())
+
+			obj := &talksv2.Proposal{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "proposal",
+					Namespace: ns.Name,
+				},
+				Spec: talksv2.ProposalSpec{
+					Title:    "this is a test proposal",
+					Abstract: "this is a test abstract",
+					Type:     "talk",
+					Final:    true,
+					SpeakerRefs: []talksv2.SpeakerRef{
+						{Name: speaker.Name},
+					},
+				},
+			}
+			g.Expect(testEnv.CreateAndWait(ctx, obj)).To(Succeed())
+
+			// Wait for Proposal to be submitted
+			key := client.ObjectKey{Name: obj.Name, Namespace: obj.Namespace}
+			g.Eventually(func() bool {
+				if err := testEnv.Get(ctx, key, obj); err != nil {
+					return false
+				}
+				return conditions.IsReady(obj) && obj.Status.Submission == talksv1.ProposalStateFinal
+			}, timeout).Should(BeTrue())
+
+			var reviews []*talksv1.Review
+			for i, score := range tc.scores {
+				review := &talksv1.Review{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      fmt.Sprintf("review-%d", i),
+						Namespace: ns.Name,
+					},
+					Spec: talksv1.ReviewSpec{
+						Reviewer:    fmt.Sprintf("reviewer-%d", i),
+						Score:       score,
+						Comments:    "test comments",
+						ProposalRef: talksv1.ProposalRef{Name: obj.Name},
+					},
+				}
+				g.Expect(testEnv.CreateAndWait(ctx, review)).To(Succeed())
+
+				// Wait for Review to be Ready
+				reviewKey := client.ObjectKey{Name: review.Name, Namespace: review.Namespace}
+				g.Eventually(func() bool {
+					if err := testEnv.Get(ctx, reviewKey, review); err != nil {
+						return false
+					}
+					return conditions.IsReady(review) && review.Status.ID != ""
+				}, timeout).Should(BeTrue())
+
+				reviews = append(reviews, review)
+			}
+
+			tc.assertFunc(obj, reviews, tc.assertConditions)
+		})
+	}
+}
+
+func Test_Review_EndpointNotAllowed(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	ns, err := testEnv.CreateNamespace(ctx, "review-endpoint-ns")
+	g.Expect(err).NotTo(HaveOccurred())
+	defer func() {
+		g.Expect(testEnv.Delete(ctx, ns)).To(Succeed())
+	}()
+
+	conference := &talksv1.Conference{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "conference",
+			Namespace: ns.Name,
+		},
+		Spec: talksv1.ConferenceSpec{
+			OpenTime:  metav1.Now(),
+			CloseTime: metav1.NewTime(metav1.Now().AddDate(0, 1, 0)),
+			Endpoint:  "http://not-allowed.example.com",
+		},
+	}
+	g.Expect(testEnv.CreateAndWait(ctx, conference)).To(Succeed())
+
+	proposal := &talksv2.Proposal{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "proposal",
+			Namespace: ns.Name,
+		},
+		Spec: talksv2.ProposalSpec{
+			Title:         "this is a test proposal",
+			Abstract:      "this is a test abstract",
+			Type:          "talk",
+			SpeakerRefs:   []talksv2.SpeakerRef{{Name: "speaker"}},
+			ConferenceRef: &talksv2.ConferenceRef{Name: conference.Name},
+		},
+	}
+	g.Expect(testEnv.CreateAndWait(ctx, proposal)).To(Succeed())
+
+	review := &talksv1.Review{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "review",
+			Namespace: ns.Name,
+		},
+		Spec: talksv1.ReviewSpec{
+			Reviewer:    "reviewer",
+			Score:       3,
+			ProposalRef: talksv1.ProposalRef{Name: proposal.Name},
+		},
+	}
+	g.Expect(testEnv.CreateAndWait(ctx, review)).To(Succeed())
+
+	// The review is not pushed to the endpoint of the conference, which is
+	// not allowed
+	key := client.ObjectKey{Name: review.Name, Namespace: review.Namespace}
+	g.Eventually(func() bool {
+		if err := testEnv.Get(ctx, key, review); err != nil {
+			return false
+		}
+		return conditions.IsStalled(review)
+	}, timeout).Should(BeTrue())
+
+	g.Expect(conditions.GetReason(review, meta.ReadyCondition)).To(Equal(talksv1.EndpointNotAllowedReason))
+	g.Expect(review.Status.ID).To(BeEmpty())
+
+	// The review can still be deleted
+	g.Expect(testEnv.Delete(ctx, review)).To(Succeed())
+	g.Eventually(func() bool {
+		return apierrors.IsNotFound(testEnv.Get(ctx, key, review))
+	}, timeout).Should(BeTrue())
+}
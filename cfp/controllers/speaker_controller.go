@@ -17,26 +17,38 @@ limitations under the License.
 package controllers
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
+	"strings"
+	"time"
 
-	"encoding/json"
-
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/fluxcd/pkg/apis/meta"
 	"github.com/fluxcd/pkg/runtime/conditions"
 	"github.com/fluxcd/pkg/runtime/patch"
+	cfptypes "github.com/scottrigby/cfp-api/pkg/types"
 
 	talksv1 "github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/api/v1"
+	talksv2 "github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/api/v2"
 	"github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/internal/cfp"
 )
 
@@ -46,19 +58,44 @@ var speakerOwnedConditions = []string{
 	meta.StalledCondition,
 	talksv1.CreateFailedCondition,
 	talksv1.UpdateFailedCondition,
+	talksv1.DriftedCondition,
+	talksv1.DeletionBlockedCondition,
 }
 
 // SpeakerReconciler reconciles a Speaker object
 type SpeakerReconciler struct {
 	client.Client
 	HTTPClient     *http.Client
+	EventRecorder  record.EventRecorder
 	ControllerName string
 	CfpAPI         string
+
+	// AllowedEndpoints are the addresses of the CFP APIs other than CfpAPI
+	// that Conferences can collect the speakers of.
+	AllowedEndpoints []string
+
+	// RateLimiter limits the requests sent to the CFP API, it is shared by
+	// the reconcilers.
+	RateLimiter *cfp.RateLimiter
+
+	// Credentials authenticate the requests sent to the CFP API, if set.
+	Credentials *cfp.CredentialStore
+
+	// DefaultInterval is the interval at which Speakers without an interval
+	// are compared with their record in the CFP API.
+	DefaultInterval time.Duration
+
+	// ClusterID identifies the cluster in the owner marker of the records
+	// created in the CFP API.
+	ClusterID string
 }
 
 //+kubebuilder:rbac:groups=talks.kubecon.na,resources=speakers,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=talks.kubecon.na,resources=speakers/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=talks.kubecon.na,resources=speakers/finalizers,verbs=update
+//+kubebuilder:rbac:groups=talks.kubecon.na,resources=proposals,verbs=get;list;watch
+//+kubebuilder:rbac:groups=talks.kubecon.na,resources=conferences,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -70,6 +107,9 @@ type SpeakerReconciler struct {
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.12.2/pkg/reconcile
 func (r *SpeakerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, retErr error) {
+	ctx, span := startReconcileSpan(ctx, "Speaker", req)
+	defer func() { endSpan(span, retErr) }()
+
 	log := log.FromContext(ctx)
 	// Fetch the Speaker instance
 	// Automatically requeue if an error is returned
@@ -79,6 +119,12 @@ func (r *SpeakerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (re
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	// A stalled object fails the same way until its spec changes, it is not
+	// reconciled again on resync or on the events of other objects
+	if skipStalled(obj, obj.Status.ObservedGeneration) {
+		return ctrl.Result{}, nil
+	}
+
 	log.Info("reconciling speaker", "speaker", obj.Name)
 
 	// Initialize the patch helper with the current version of the object.
@@ -124,12 +170,54 @@ func (r *SpeakerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (re
 		return ctrl.Result{Requeue: true}, nil
 	}
 
+	// Wait for the proposals referencing the speaker to be deleted or changed
+	// before deleting it, even while it is suspended. The speaker is
+	// reconciled again once they are.
+	if !obj.ObjectMeta.DeletionTimestamp.IsZero() {
+		if blocked, err := r.deletionBlocked(ctx, obj); blocked || err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Skip the calls to the CFP API while the object is suspended.
+	// A suspended object can still be deleted, in which case its finalizer is
+	// removed without deleting it from the CFP API.
+	if obj.Spec.Suspend {
+		if !obj.ObjectMeta.DeletionTimestamp.IsZero() {
+			controllerutil.RemoveFinalizer(obj, talksv1.Finalizer)
+			return ctrl.Result{}, nil
+		}
+		log.Info("reconciliation is suspended for this object")
+		r.EventRecorder.Event(obj, corev1.EventTypeNormal, meta.SuspendedReason, "reconciliation is suspended")
+		conditions.MarkFalse(obj, meta.ReadyCondition, meta.SuspendedReason, "reconciliation is suspended")
+		return ctrl.Result{}, nil
+	}
+
+	// Get the address of the CFP API holding the record of the speaker.
+	// Only the allowed endpoints are sent requests: a speaker being deleted
+	// keeps its finalizer until the endpoint of its record is allowed again.
+	_, endpoint, err := r.resolveEndpoint(ctx, obj)
+	if err != nil {
+		var stallErr *stallingError
+		if errors.As(err, &stallErr) {
+			conditions.MarkStalled(obj, stallErr.Reason, err.Error())
+			conditions.MarkFalse(obj, meta.ReadyCondition, stallErr.Reason, err.Error())
+			r.EventRecorder.Event(obj, corev1.EventTypeWarning, stallErr.Reason, err.Error())
+			return ctrl.Result{}, nil
+		}
+		conditions.MarkFalse(obj, meta.ReadyCondition, talksv1.FetchFailedReason, err.Error())
+		r.EventRecorder.Event(obj, corev1.EventTypeWarning, talksv1.FetchFailedReason, err.Error())
+		return ctrl.Result{}, err
+	}
+
 	//create a new cfp client
-	cfpClient, err := cfp.NewClient(r.CfpAPI, r.HTTPClient)
+	cfpClient, err := cfp.NewClient(endpoint, r.HTTPClient)
 	if err != nil {
 		conditions.MarkStalled(obj, talksv1.CreateFailedCondition, "Failed to create cfp client")
 		return ctrl.Result{}, err
 	}
+	cfpClient.Limiter = r.RateLimiter
+	cfpClient.Credentials = r.Credentials
 
 	// Check if this a deletion, if yes api call to delete the Speaker
 	if !obj.ObjectMeta.DeletionTimestamp.IsZero() {
@@ -147,8 +235,31 @@ func (r *SpeakerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (re
 func (r *SpeakerReconciler) reconcile(ctx context.Context, obj *talksv1.Speaker, client *cfp.Client) (result ctrl.Result, retErr error) {
 	// defer func attempt to set the Ready condition and unset all needed conditions based on the reconciliation
 	defer func() {
+		// A stalled reconciliation is not retried until the object changes,
+		// a waiting one is retried once the wait is over.
+		retErr = classifyError(retErr)
+		var (
+			stallErr *stallingError
+			waitErr  *waitingError
+		)
+		switch {
+		case errors.As(retErr, &stallErr):
+			conditions.MarkStalled(obj, stallErr.Reason, stallErr.Error())
+			conditions.MarkFalse(obj, meta.ReadyCondition, stallErr.Reason, stallErr.Error())
+			r.EventRecorder.Event(obj, corev1.EventTypeWarning, stallErr.Reason, stallErr.Error())
+			result, retErr = ctrl.Result{}, nil
+			return
+		case errors.As(retErr, &waitErr):
+			conditions.Delete(obj, meta.StalledCondition)
+			conditions.MarkFalse(obj, meta.ReadyCondition, waitErr.Reason, waitErr.Error())
+			r.EventRecorder.Event(obj, corev1.EventTypeNormal, waitErr.Reason, waitErr.Error())
+			result, retErr = ctrl.Result{RequeueAfter: waitErr.RequeueAfter}, nil
+			return
+		}
+
 		if !result.Requeue && retErr == nil {
 			conditions.Delete(obj, meta.ReconcilingCondition)
+			conditions.Delete(obj, meta.StalledCondition)
 			conditions.Delete(obj, talksv1.CreateFailedCondition)
 			conditions.Delete(obj, talksv1.UpdateFailedCondition)
 			conditions.MarkTrue(obj, meta.ReadyCondition, meta.SucceededReason, "reconciled '%s' successfully", obj.Name)
@@ -164,16 +275,17 @@ func (r *SpeakerReconciler) reconcile(ctx context.Context, obj *talksv1.Speaker,
 			}
 			switch apiErr.Reason {
 			case cfp.ErrCreateSpeaker:
-				conditions.MarkTrue(obj, talksv1.CreateFailedCondition, apiErr.Reason.Reason, apiErr.Error())
-				conditions.MarkFalse(obj, meta.ReadyCondition, apiErr.Reason.Reason, apiErr.Error())
+				conditions.MarkTrue(obj, talksv1.CreateFailedCondition, apiErrorReason(apiErr), apiErr.Error())
+				conditions.MarkFalse(obj, meta.ReadyCondition, apiErrorReason(apiErr), apiErr.Error())
 			case cfp.ErrUpdateSpeaker:
-				conditions.MarkTrue(obj, talksv1.UpdateFailedCondition, apiErr.Reason.Reason, apiErr.Error())
-				conditions.MarkFalse(obj, meta.ReadyCondition, apiErr.Reason.Reason, apiErr.Error())
+				conditions.MarkTrue(obj, talksv1.UpdateFailedCondition, apiErrorReason(apiErr), apiErr.Error())
+				conditions.MarkFalse(obj, meta.ReadyCondition, apiErrorReason(apiErr), apiErr.Error())
 			case cfp.ErrCreateRequest, cfp.ErrMakeRequest, cfp.ErrFetchSpeaker:
-				conditions.MarkFalse(obj, meta.ReadyCondition, apiErr.Reason.Reason, apiErr.Error())
+				conditions.MarkFalse(obj, meta.ReadyCondition, apiErrorReason(apiErr), apiErr.Error())
 			default:
 				conditions.MarkFalse(obj, meta.ReadyCondition, meta.FailedReason, apiErr.Error())
 			}
+			r.EventRecorder.Event(obj, corev1.EventTypeWarning, conditions.GetReason(obj, meta.ReadyCondition), conditions.GetMessage(obj, meta.ReadyCondition))
 		}
 	}()
 
@@ -190,43 +302,176 @@ func (r *SpeakerReconciler) reconcile(ctx context.Context, obj *talksv1.Speaker,
 	// error and requeue
 	if obj.Status.ID != "" {
 		err := r.handleSpeakerUpdate(ctx, obj, client)
+		if cfp.IsNotFound(err) {
+			// The record of the Speaker was deleted outside of the cluster,
+			// create it again with the same ID
+			r.EventRecorder.Eventf(obj, corev1.EventTypeWarning, speakerRecreatedReason,
+				"speaker '%s' was deleted from the CFP API outside of the cluster, recreating it", obj.Status.ID)
+			err = r.createSpeaker(ctx, obj, client)
+		}
 		if err != nil {
 			return ctrl.Result{}, err
 		}
-		return ctrl.Result{}, nil
+		obj.Status.Endpoint = client.Endpoint()
+		obj.Status.LastSyncTime = &metav1.Time{Time: time.Now()}
+		return ctrl.Result{RequeueAfter: r.requeueAfter(obj)}, nil
+	}
+
+	// Adopt the existing record of the Speaker, and converge it to the spec.
+	// The record is updated even if it already matches the spec, so that it
+	// carries the owner marker of the Speaker.
+	if obj.Spec.ImportID != "" {
+		err := retry.OnError(retry.DefaultRetry, cfp.IsPreconditionFailed, func() error {
+			remote, err := client.Speakers().Get(ctx, obj.Spec.ImportID)
+			if err != nil {
+				return err
+			}
+			record := speakerRecord(obj, ownerMarker(r.ClusterID, obj))
+			record.ID = obj.Spec.ImportID
+			record.ETag = remote.ETag
+			_, err = client.Speakers().Update(ctx, record)
+			return err
+		})
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		obj.Status.ID = obj.Spec.ImportID
+		obj.Status.Endpoint = client.Endpoint()
+		r.EventRecorder.Eventf(obj, corev1.EventTypeNormal, speakerAdoptedReason, "adopted speaker '%s' of the CFP API", obj.Status.ID)
+		obj.Status.LastSyncTime = &metav1.Time{Time: time.Now()}
+		return ctrl.Result{RequeueAfter: r.requeueAfter(obj)}, nil
 	}
 
 	// Create the Speaker
 	err := r.createSpeaker(ctx, obj, client)
+	if cfp.IsConflict(err) {
+		// The record may have been created by a previous reconciliation whose
+		// status was lost, in which case it carries the owner marker of the
+		// Speaker and is adopted
+		remote, getErr := client.Speakers().Get(ctx, speakerID(obj))
+		if getErr != nil {
+			return ctrl.Result{}, getErr
+		}
+		// Creating the speaker again can not succeed, it must be adopted with
+		// an import ID, or use another name
+		if remote.Owner != ownerMarker(r.ClusterID, obj) {
+			return ctrl.Result{}, &stallingError{
+				Reason: talksv1.AlreadyExistsReason,
+				Err:    fmt.Errorf("%w, set spec.importID to adopt it", err),
+			}
+		}
+		obj.Status.ID = speakerID(obj)
+		obj.Status.Endpoint = client.Endpoint()
+		r.EventRecorder.Eventf(obj, corev1.EventTypeNormal, speakerAdoptedReason, "adopted its own speaker '%s' of the CFP API", obj.Status.ID)
+		err = r.handleSpeakerUpdate(ctx, obj, client)
+	}
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
-	// Set the ID in the status
-	obj.Status.ID = fmt.Sprintf("%s-%s", obj.Namespace, obj.Name)
-	return ctrl.Result{}, nil
+	// Set the ID in the status, along with the CFP API holding the record
+	obj.Status.ID = speakerID(obj)
+	obj.Status.Endpoint = client.Endpoint()
+	obj.Status.LastSyncTime = &metav1.Time{Time: time.Now()}
+	return ctrl.Result{RequeueAfter: r.requeueAfter(obj)}, nil
 }
 
-func (r *SpeakerReconciler) handleSpeakerUpdate(ctx context.Context, obj *talksv1.Speaker, client *cfp.Client) error {
-	// Make a call to the API to update obj
-	body, err := createSpeakerPayload(obj)
-	if err != nil {
-		return fmt.Errorf("error creating request: %w", err)
+// resolveEndpoint returns the conference of the Speaker, if any, and the
+// address of the CFP API holding its record. The record of a Speaker being
+// deleted is deleted from the CFP API recorded in its status, rather than from
+// the one of its conference which may be gone or have changed.
+func (r *SpeakerReconciler) resolveEndpoint(ctx context.Context, obj *talksv1.Speaker) (*talksv1.Conference, string, error) {
+	if !obj.ObjectMeta.DeletionTimestamp.IsZero() {
+		switch {
+		case obj.Status.Endpoint != "":
+			endpoint, err := recordedEndpoint(obj.Status.Endpoint, r.CfpAPI, r.AllowedEndpoints)
+			return nil, endpoint, err
+		case obj.Status.ID == "":
+			// There is no record to delete from the CFP API
+			return nil, r.CfpAPI, nil
+		}
 	}
 
-	// Get the Speaker
-	payload, err := client.Get(ctx, cfp.SpeakerPath, obj.Status.ID)
+	conference, err := r.getConference(ctx, obj)
 	if err != nil {
-		return err
+		return nil, "", err
+	}
+	endpoint, err := cfpEndpoint(conference, r.CfpAPI, r.AllowedEndpoints)
+	return conference, endpoint, err
+}
+
+// getConference returns the conference referenced by the Speaker, or nil if
+// the Speaker does not reference any.
+func (r *SpeakerReconciler) getConference(ctx context.Context, obj *talksv1.Speaker) (*talksv1.Conference, error) {
+	if obj.Spec.ConferenceRef == nil {
+		return nil, nil
+	}
+
+	conference := &talksv1.Conference{}
+	namespacedName := types.NamespacedName{Namespace: obj.Namespace, Name: obj.Spec.ConferenceRef.Name}
+	if obj.Spec.ConferenceRef.Namespace != "" {
+		namespacedName.Namespace = obj.Spec.ConferenceRef.Namespace
+	}
+	if err := r.Get(ctx, namespacedName, conference); err != nil {
+		return nil, fmt.Errorf("unable to get conference %s: %w", namespacedName.String(), err)
+	}
+	return conference, nil
+}
+
+// requeueAfter returns the interval at which the Speaker is compared with its
+// record in the CFP API.
+func (r *SpeakerReconciler) requeueAfter(obj *talksv1.Speaker) time.Duration {
+	if d := obj.GetRequeueAfter(); d > 0 {
+		return d
 	}
+	return r.DefaultInterval
+}
 
-	// Check if the payload is the same
-	// if not, it means we have changed the spec, so we need to update the speaker
-	if !bytes.Equal(payload, body) {
-		_, err = client.Update(ctx, cfp.SpeakerPath, obj.Status.ID, body)
+func (r *SpeakerReconciler) handleSpeakerUpdate(ctx context.Context, obj *talksv1.Speaker, client *cfp.Client) error {
+	if obj.Generation != obj.Status.ObservedGeneration {
+		conditions.Delete(obj, talksv1.DriftedCondition)
+	}
+
+	// The update only applies to the record it was compared with, if the
+	// record is modified meanwhile it is read and compared again
+	var drifted []string
+	err := retry.OnError(retry.DefaultRetry, cfp.IsPreconditionFailed, func() error {
+		// Get the Speaker
+		remote, err := client.Speakers().Get(ctx, obj.Status.ID)
 		if err != nil {
 			return err
 		}
+
+		// Compare the fields we own with the record
+		// if any differs, we have either changed the spec or the record drifted,
+		// so we need to update the speaker
+		drifted = driftedFields(ownedSpeakerFields(obj), speakerRecordFields(remote))
+		if len(drifted) == 0 {
+			return nil
+		}
+
+		// Make a call to the API to update obj
+		record := speakerRecord(obj, ownerMarker(r.ClusterID, obj))
+		record.ETag = remote.ETag
+		_, err = client.Speakers().Update(ctx, record)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	// A record matching the Speaker no longer drifts
+	if len(drifted) == 0 {
+		conditions.Delete(obj, talksv1.DriftedCondition)
+		return nil
+	}
+	r.EventRecorder.Eventf(obj, corev1.EventTypeNormal, speakerUpdatedReason, "updated speaker '%s' in the CFP API", obj.Status.ID)
+
+	// The record drifted if the spec did not change since the last reconciliation
+	if obj.Generation == obj.Status.ObservedGeneration {
+		recordDrift("Speaker", drifted)
+		conditions.MarkTrue(obj, talksv1.DriftedCondition, talksv1.DriftCorrectedReason,
+			"corrected drifted fields of the CFP API record: %s", strings.Join(drifted, ", "))
+		r.EventRecorder.Event(obj, corev1.EventTypeWarning, talksv1.DriftCorrectedReason, conditions.GetMessage(obj, talksv1.DriftedCondition))
 	}
 
 	return nil
@@ -234,29 +479,28 @@ func (r *SpeakerReconciler) handleSpeakerUpdate(ctx context.Context, obj *talksv
 
 // createSpeaker will create a Speaker in the CFP API
 func (r *SpeakerReconciler) createSpeaker(ctx context.Context, obj *talksv1.Speaker, client *cfp.Client) error {
-	// Make a call to the API to update obj
-	body, err := createSpeakerPayload(obj)
-	if err != nil {
-		return fmt.Errorf("error creating request: %w", err)
-	}
-
-	_, err = client.Create(ctx, cfp.SpeakerPath, body)
+	// Make a call to the API to create obj
+	_, err := client.Speakers().Create(ctx, speakerRecord(obj, ownerMarker(r.ClusterID, obj)))
 	if err != nil {
 		return err
 	}
+	r.EventRecorder.Eventf(obj, corev1.EventTypeNormal, speakerCreatedReason, "created speaker '%s' in the CFP API", speakerID(obj))
 
 	return nil
 }
 
 // reconcileDelete will delete the obj from the CFP API
 func (r *SpeakerReconciler) reconcileDelete(ctx context.Context, obj *talksv1.Speaker, client *cfp.Client) (ctrl.Result, error) {
-	// api call to delete the Speaker if necessary
-	if obj.Status.ID != "" {
-		err := client.Delete(ctx, cfp.SpeakerPath, obj.Status.ID)
-		if err != nil {
+	// api call to delete the Speaker if necessary, unless its record is retained
+	if obj.Status.ID != "" && obj.Spec.DeletionPolicy != talksv1.DeletionPolicyRetain {
+		// A record which is not found has already been deleted
+		err := client.Speakers().Delete(ctx, obj.Status.ID)
+		if err != nil && !cfp.IsNotFound(err) {
+			r.EventRecorder.Event(obj, corev1.EventTypeWarning, failureReason(err), err.Error())
 			// return the error so we can requeue
 			return ctrl.Result{}, err
 		}
+		r.EventRecorder.Eventf(obj, corev1.EventTypeNormal, speakerDeletedReason, "deleted speaker '%s' from the CFP API", obj.Status.ID)
 	}
 	// clean the finalizer
 	controllerutil.RemoveFinalizer(obj, talksv1.Finalizer)
@@ -265,30 +509,129 @@ func (r *SpeakerReconciler) reconcileDelete(ctx context.Context, obj *talksv1.Sp
 	return ctrl.Result{}, nil
 }
 
-// SetupWithManager sets up the controller with the Manager.
+// deletionBlocked returns true if the deletion of the Speaker must wait for
+// the Proposals referencing it to be deleted or changed, so that their record
+// does not keep a dangling speaker ID. The blocking Proposals are listed in
+// the DeletionBlocked condition. Speakers whose record is retained are not
+// blocked.
+func (r *SpeakerReconciler) deletionBlocked(ctx context.Context, obj *talksv1.Speaker) (bool, error) {
+	if obj.Status.ID == "" || obj.Spec.DeletionPolicy == talksv1.DeletionPolicyRetain {
+		return false, nil
+	}
+
+	proposals, err := r.referencingProposals(ctx, obj)
+	if err != nil {
+		return false, err
+	}
+	if len(proposals) > 0 {
+		msg := fmt.Sprintf("speaker is referenced by proposals: %s", strings.Join(proposals, ", "))
+		conditions.MarkTrue(obj, talksv1.DeletionBlockedCondition, talksv1.ReferencedByProposalsReason, msg)
+		conditions.MarkFalse(obj, meta.ReadyCondition, talksv1.ReferencedByProposalsReason, msg)
+		r.EventRecorder.Event(obj, corev1.EventTypeWarning, talksv1.ReferencedByProposalsReason, msg)
+		return true, nil
+	}
+	conditions.Delete(obj, talksv1.DeletionBlockedCondition)
+	return false, nil
+}
+
+// referencingProposals returns the namespace/name of the Proposals
+// referencing the Speaker, sorted. They are looked up with the index of the
+// Proposals by speaker, set up by the ProposalReconciler.
+func (r *SpeakerReconciler) referencingProposals(ctx context.Context, obj *talksv1.Speaker) ([]string, error) {
+	var list talksv2.ProposalList
+	if err := r.List(ctx, &list, client.MatchingFields{talksv1.SpeakerIndexKey: client.ObjectKeyFromObject(obj).String()}); err != nil {
+		return nil, fmt.Errorf("unable to list proposals: %w", err)
+	}
+
+	names := make([]string, 0, len(list.Items))
+	for i := range list.Items {
+		names = append(names, client.ObjectKeyFromObject(&list.Items[i]).String())
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// requestsForProposalChange requeues the speakers referenced by a proposal
+// whose deletion may be waiting for the proposal to stop referencing them.
+func (r *SpeakerReconciler) requestsForProposalChange(o client.Object) []reconcile.Request {
+	proposal, ok := o.(*talksv2.Proposal)
+	if !ok {
+		panic(fmt.Sprintf("Expected a Proposal, got %T", o))
+	}
+
+	ctx := context.Background()
+	var reqs []reconcile.Request
+	for _, ref := range proposal.Spec.SpeakerRefs {
+		var speaker talksv1.Speaker
+		if err := r.Get(ctx, speakerKey(proposal, ref), &speaker); err != nil {
+			continue
+		}
+		if !speaker.DeletionTimestamp.IsZero() {
+			reqs = append(reqs, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&speaker)})
+		}
+	}
+	return reqs
+}
+
+// SetupWithManager sets up the controller with the Manager, with the default
+// options.
 func (r *SpeakerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return r.SetupWithManagerAndOptions(mgr, ReconcilerOptions{})
+}
+
+// SetupWithManagerAndOptions sets up the controller with the Manager and the
+// given options.
+func (r *SpeakerReconciler) SetupWithManagerAndOptions(mgr ctrl.Manager, opts ReconcilerOptions) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&talksv1.Speaker{}).
+		WithOptions(opts.controllerOptions()).
+		Watches(
+			&source.Kind{Type: &talksv2.Proposal{}},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForProposalChange),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
 		Complete(r)
 }
 
-func createSpeakerPayload(obj *talksv1.Speaker) ([]byte, error) {
-	body := struct {
-		ID    string `json:"id"`
-		Name  string `json:"name"`
-		Bio   string `json:"bio"`
-		Email string `json:"email"`
-	}{
-		ID:    fmt.Sprintf("%s-%s", obj.Namespace, obj.Name),
+// speakerFields are the fields of a CFP API speaker record owned by a Speaker.
+type speakerFields struct {
+	Name  string `json:"name"`
+	Bio   string `json:"bio"`
+	Email string `json:"email"`
+}
+
+func ownedSpeakerFields(obj *talksv1.Speaker) speakerFields {
+	return speakerFields{
 		Name:  obj.Spec.Name,
 		Bio:   obj.Spec.Bio,
 		Email: obj.Spec.Email,
 	}
+}
 
-	payload, err := json.Marshal(body)
-	if err != nil {
-		return nil, fmt.Errorf("error marshalling body: %w", err)
+func speakerRecordFields(s *cfptypes.Speaker) speakerFields {
+	return speakerFields{
+		Name:  s.Name,
+		Bio:   s.Bio,
+		Email: s.Email,
 	}
+}
 
-	return payload, nil
+// speakerID returns the ID of the record of the Speaker in the CFP API, which
+// is the ID of the adopted record if any, or derived from its namespace/name.
+func speakerID(obj *talksv1.Speaker) string {
+	if obj.Status.ID != "" {
+		return obj.Status.ID
+	}
+	return fmt.Sprintf("%s-%s", obj.Namespace, obj.Name)
+}
+
+// speakerRecord returns the CFP API record of the Speaker.
+func speakerRecord(obj *talksv1.Speaker, owner string) *cfptypes.Speaker {
+	return &cfptypes.Speaker{
+		ID:    speakerID(obj),
+		Name:  obj.Spec.Name,
+		Bio:   obj.Spec.Bio,
+		Email: obj.Spec.Email,
+		Owner: owner,
+	}
 }
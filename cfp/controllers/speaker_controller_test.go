@@ -1,8 +1,12 @@
 package controllers
 
 import (
+	"fmt"
+	"net/http"
+	"os"
 	"strings"
 	"testing"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -12,6 +16,7 @@ import (
 	"github.com/fluxcd/pkg/runtime/patch"
 	. "github.com/onsi/gomega"
 	talksv1 "github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/api/v1"
+	"github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/internal/cfp"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
@@ -123,6 +128,217 @@ func Test_Speaker_Reconcile(t *testing.T) {
 
 			},
 		},
+		{
+			name:    "test periodic speaker reconciliation",
+			Speaker: "Saul Goodman",
+			Bio:     "Criminal lawyer",
+			Email:   "better.call.saul@protonmail.com",
+			assertConditions: []metav1.Condition{
+				*conditions.TrueCondition(meta.ReadyCondition, meta.SucceededReason, "reconciled '<name>' successfully"),
+			},
+			assertFunc: func(obj *talksv1.Speaker, assertConditions []metav1.Condition) {
+				key := client.ObjectKey{Name: obj.Name, Namespace: obj.Namespace}
+				// Wait for Speaker to be synchronized
+				g.Eventually(func() bool {
+					if err := testEnv.Get(ctx, key, obj); err != nil {
+						return false
+					}
+					return conditions.IsReady(obj) && obj.Status.LastSyncTime != nil
+				}, timeout).Should(BeTrue())
+
+				// Compare the Speaker with the CFP API every second
+				lastSyncTime := obj.Status.LastSyncTime
+				patch, err := patch.NewHelper(obj, testEnv)
+				g.Expect(err).ToNot(HaveOccurred())
+				obj.Spec.Interval = &metav1.Duration{Duration: time.Second}
+				g.Expect(patch.Patch(ctx, obj)).To(Succeed())
+
+				// Wait for Speaker to be synchronized again without any change
+				g.Eventually(func() bool {
+					if err := testEnv.Get(ctx, key, obj); err != nil {
+						return false
+					}
+					return obj.Status.LastSyncTime.After(lastSyncTime.Add(time.Second))
+				}, timeout).Should(BeTrue())
+
+				for k := range assertConditions {
+					assertConditions[k].Message = strings.ReplaceAll(assertConditions[k].Message, "<name>", obj.Name)
+				}
+				g.Expect(obj.Status.Conditions).To(conditions.MatchConditions(assertConditions))
+			},
+		},
+		{
+			name:    "test speaker drift correction",
+			Speaker: "Mike Ehrmantraut",
+			Bio:     "Retired police officer",
+			Email:   "mike@protonmail.com",
+			assertConditions: []metav1.Condition{
+				*conditions.TrueCondition(meta.ReadyCondition, meta.SucceededReason, "reconciled '<name>' successfully"),
+				*conditions.TrueCondition(talksv1.DriftedCondition, talksv1.DriftCorrectedReason, "corrected drifted fields of the CFP API record: bio"),
+			},
+			assertFunc: func(obj *talksv1.Speaker, assertConditions []metav1.Condition) {
+				key := client.ObjectKey{Name: obj.Name, Namespace: obj.Namespace}
+				// Wait for Speaker to be Ready
+				g.Eventually(func() bool {
+					if err := testEnv.Get(ctx, key, obj); err != nil {
+						return false
+					}
+					if !conditions.IsReady(obj) {
+						return false
+					}
+					readyCondition := conditions.Get(obj, meta.ReadyCondition)
+					return obj.Generation == readyCondition.ObservedGeneration &&
+						obj.Generation == obj.Status.ObservedGeneration
+				}, timeout).Should(BeTrue())
+
+				// Compare the Speaker with the CFP API every second
+				patch, err := patch.NewHelper(obj, testEnv)
+				g.Expect(err).ToNot(HaveOccurred())
+				obj.Spec.Interval = &metav1.Duration{Duration: time.Second}
+				g.Expect(patch.Patch(ctx, obj)).To(Succeed())
+
+				// Wait for Speaker to be Ready for this new generation
+				g.Eventually(func() bool {
+					if err := testEnv.Get(ctx, key, obj); err != nil {
+						return false
+					}
+					return conditions.IsReady(obj) && obj.Generation == obj.Status.ObservedGeneration
+				}, timeout).Should(BeTrue())
+
+				// Change the record outside of the cluster
+				changed := obj.DeepCopy()
+				changed.Spec.Bio = "Changed outside of the cluster"
+				cfpClient, err := cfp.NewClient(os.Getenv("CFP_API_ENDPOINT"), http.DefaultClient)
+				g.Expect(err).ToNot(HaveOccurred())
+				_, err = cfpClient.Speakers().Update(ctx, speakerRecord(changed, ownerMarker("test-cluster", changed)))
+				g.Expect(err).ToNot(HaveOccurred())
+
+				// Wait for the drift to be corrected
+				g.Eventually(func() bool {
+					if err := testEnv.Get(ctx, key, obj); err != nil {
+						return false
+					}
+					return conditions.IsTrue(obj, talksv1.DriftedCondition)
+				}, timeout).Should(BeTrue())
+
+				remote, err := cfpClient.Speakers().Get(ctx, obj.Status.ID)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(speakerRecordFields(remote)).To(Equal(ownedSpeakerFields(obj)))
+
+				for k := range assertConditions {
+					assertConditions[k].Message = strings.ReplaceAll(assertConditions[k].Message, "<name>", obj.Name)
+				}
+				g.Expect(obj.Status.Conditions).To(conditions.MatchConditions(assertConditions))
+
+				// The condition is removed once the corrected record is found
+				// matching the Speaker
+				g.Eventually(func() bool {
+					if err := testEnv.Get(ctx, key, obj); err != nil {
+						return false
+					}
+					return !conditions.Has(obj, talksv1.DriftedCondition) && conditions.IsReady(obj)
+				}, timeout).Should(BeTrue())
+			},
+		},
+		{
+			name:    "test speaker record deleted outside of the cluster",
+			Speaker: "Gustavo Fring",
+			Bio:     "Restaurant owner",
+			Email:   "gus@protonmail.com",
+			assertConditions: []metav1.Condition{
+				*conditions.TrueCondition(meta.ReadyCondition, meta.SucceededReason, "reconciled '<name>' successfully"),
+			},
+			assertFunc: func(obj *talksv1.Speaker, assertConditions []metav1.Condition) {
+				key := client.ObjectKey{Name: obj.Name, Namespace: obj.Namespace}
+				// Wait for Speaker to be Ready
+				g.Eventually(func() bool {
+					if err := testEnv.Get(ctx, key, obj); err != nil {
+						return false
+					}
+					return conditions.IsReady(obj) && obj.Status.ID != ""
+				}, timeout).Should(BeTrue())
+
+				// Compare the Speaker with the CFP API every second
+				patch, err := patch.NewHelper(obj, testEnv)
+				g.Expect(err).ToNot(HaveOccurred())
+				obj.Spec.Interval = &metav1.Duration{Duration: time.Second}
+				g.Expect(patch.Patch(ctx, obj)).To(Succeed())
+
+				// Delete the record outside of the cluster
+				cfpClient, err := cfp.NewClient(os.Getenv("CFP_API_ENDPOINT"), http.DefaultClient)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(cfpClient.Speakers().Delete(ctx, obj.Status.ID)).To(Succeed())
+
+				// Wait for the record to be created again
+				g.Eventually(func() bool {
+					remote, err := cfpClient.Speakers().Get(ctx, obj.Status.ID)
+					return err == nil && remote.Owner == ownerMarker("test-cluster", obj)
+				}, timeout).Should(BeTrue())
+
+				g.Eventually(func() bool {
+					if err := testEnv.Get(ctx, key, obj); err != nil {
+						return false
+					}
+					return conditions.IsReady(obj)
+				}, timeout).Should(BeTrue())
+
+				for k := range assertConditions {
+					assertConditions[k].Message = strings.ReplaceAll(assertConditions[k].Message, "<name>", obj.Name)
+				}
+				g.Expect(obj.Status.Conditions).To(conditions.MatchConditions(assertConditions))
+			},
+		},
+		{
+			name:    "test suspend speaker reconciliation",
+			Speaker: "Walter White",
+			Bio:     "Chemistry teacher",
+			Email:   "heisenberg@protonmail.com",
+			assertConditions: []metav1.Condition{
+				*conditions.FalseCondition(meta.ReadyCondition, meta.SuspendedReason, "reconciliation is suspended"),
+			},
+			assertFunc: func(obj *talksv1.Speaker, assertConditions []metav1.Condition) {
+				key := client.ObjectKey{Name: obj.Name, Namespace: obj.Namespace}
+				// Wait for Speaker to be Ready
+				g.Eventually(func() bool {
+					if err := testEnv.Get(ctx, key, obj); err != nil {
+						return false
+					}
+					if !conditions.IsReady(obj) {
+						return false
+					}
+					readyCondition := conditions.Get(obj, meta.ReadyCondition)
+					return obj.Generation == readyCondition.ObservedGeneration &&
+						obj.Generation == obj.Status.ObservedGeneration
+				}, timeout).Should(BeTrue())
+
+				// Suspend Speaker
+				patch, err := patch.NewHelper(obj, testEnv)
+				g.Expect(err).ToNot(HaveOccurred())
+				obj.Spec.Suspend = true
+				g.Expect(patch.Patch(ctx, obj)).To(Succeed())
+
+				// Wait for Speaker to be suspended
+				g.Eventually(func() bool {
+					if err := testEnv.Get(ctx, key, obj); err != nil {
+						return false
+					}
+					return conditions.GetReason(obj, meta.ReadyCondition) == meta.SuspendedReason
+				}, timeout).Should(BeTrue())
+
+				g.Expect(obj.Status.Conditions).To(conditions.MatchConditions(assertConditions))
+
+				// Delete the suspended Speaker
+				g.Expect(testEnv.Delete(ctx, obj)).To(Succeed())
+
+				// Wait for Speaker to be deleted
+				g.Eventually(func() bool {
+					if err := testEnv.Get(ctx, key, obj); err != nil {
+						return apierrors.IsNotFound(err)
+					}
+					return false
+				}, timeout).Should(BeTrue())
+			},
+		},
 		{
 			name:    "test delete speaker reconciliation",
 			Speaker: "Jesse Pinkman",
@@ -163,6 +379,44 @@ func Test_Speaker_Reconcile(t *testing.T) {
 				}, timeout).Should(BeTrue())
 			},
 		},
+		{
+			name:    "test delete speaker with the Retain policy",
+			Speaker: "Skyler White",
+			Bio:     "Bookkeeper",
+			Email:   "skyler.white@protonmail.com",
+			assertFunc: func(obj *talksv1.Speaker, assertConditions []metav1.Condition) {
+				key := client.ObjectKey{Name: obj.Name, Namespace: obj.Namespace}
+				// Wait for Speaker to be Ready
+				g.Eventually(func() bool {
+					if err := testEnv.Get(ctx, key, obj); err != nil {
+						return false
+					}
+					return conditions.IsReady(obj) && obj.Status.ID != ""
+				}, timeout).Should(BeTrue())
+
+				patchHelper, err := patch.NewHelper(obj, testEnv)
+				g.Expect(err).ToNot(HaveOccurred())
+				obj.Spec.DeletionPolicy = talksv1.DeletionPolicyRetain
+				g.Expect(patchHelper.Patch(ctx, obj)).To(Succeed())
+
+				// Delete Speaker
+				g.Expect(testEnv.Delete(ctx, obj)).To(Succeed())
+
+				// Wait for Speaker to be deleted
+				g.Eventually(func() bool {
+					if err := testEnv.Get(ctx, key, obj); err != nil {
+						return apierrors.IsNotFound(err)
+					}
+					return false
+				}, timeout).Should(BeTrue())
+
+				// The record of the speaker is kept in the CFP API
+				cfpClient, err := cfp.NewClient(os.Getenv("CFP_API_ENDPOINT"), http.DefaultClient)
+				g.Expect(err).ToNot(HaveOccurred())
+				_, err = cfpClient.Speakers().Get(ctx, obj.Status.ID)
+				g.Expect(err).ToNot(HaveOccurred())
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -192,3 +446,166 @@ func Test_Speaker_Reconcile(t *testing.T) {
 		})
 	}
 }
+
+func Test_Speaker_Adoption(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	ns, err := testEnv.CreateNamespace(ctx, "speaker-ns")
+	g.Expect(err).NotTo(HaveOccurred())
+	defer func() {
+		g.Expect(testEnv.Delete(ctx, ns)).To(Succeed())
+	}()
+
+	obj := &talksv1.Speaker{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "speaker",
+			Namespace: ns.Name,
+		},
+		Spec: talksv1.SpeakerSpec{
+			Name:  "Gustavo Fring",
+			Bio:   "Owner of Los Pollos Hermanos",
+			Email: "gus@lospolloshermanos.com",
+		},
+	}
+
+	// Register the speaker in the CFP API outside of the cluster
+	existing := obj.DeepCopy()
+	existing.Spec.Bio = "Registered outside of the cluster"
+	cfpClient, err := cfp.NewClient(os.Getenv("CFP_API_ENDPOINT"), http.DefaultClient)
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = cfpClient.Speakers().Create(ctx, speakerRecord(existing, ""))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(testEnv.CreateAndWait(ctx, obj)).To(Succeed())
+
+	key := client.ObjectKeyFromObject(obj)
+	// Wait for Speaker to be Stalled, its ID is already taken
+	g.Eventually(func() bool {
+		if err := testEnv.Get(ctx, key, obj); err != nil {
+			return false
+		}
+		return conditions.IsStalled(obj)
+	}, timeout).Should(BeTrue())
+	g.Expect(conditions.GetReason(obj, meta.ReadyCondition)).To(Equal(talksv1.AlreadyExistsReason))
+	g.Expect(obj.Status.ID).To(BeEmpty())
+
+	// Adopt the existing record
+	patchHelper, err := patch.NewHelper(obj, testEnv)
+	g.Expect(err).ToNot(HaveOccurred())
+	obj.Spec.ImportID = fmt.Sprintf("%s-%s", obj.Namespace, obj.Name)
+	g.Expect(patchHelper.Patch(ctx, obj)).To(Succeed())
+
+	// Wait for Speaker to be Ready
+	g.Eventually(func() bool {
+		if err := testEnv.Get(ctx, key, obj); err != nil {
+			return false
+		}
+		return conditions.IsReady(obj) && obj.Generation == obj.Status.ObservedGeneration
+	}, timeout).Should(BeTrue())
+	g.Expect(conditions.Has(obj, meta.StalledCondition)).To(BeFalse())
+	g.Expect(obj.Status.ID).To(Equal(obj.Spec.ImportID))
+
+	// The adopted record converged to the spec
+	remote, err := cfpClient.Speakers().Get(ctx, obj.Status.ID)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(speakerRecordFields(remote)).To(Equal(ownedSpeakerFields(obj)))
+	g.Expect(remote.Owner).To(Equal(ownerMarker("test-cluster", obj)))
+}
+
+func Test_Speaker_AdoptMatchingRecord(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	ns, err := testEnv.CreateNamespace(ctx, "speaker-ns")
+	g.Expect(err).NotTo(HaveOccurred())
+	defer func() {
+		g.Expect(testEnv.Delete(ctx, ns)).To(Succeed())
+	}()
+
+	obj := &talksv1.Speaker{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "speaker",
+			Namespace: ns.Name,
+		},
+		Spec: talksv1.SpeakerSpec{
+			Name:     "Lydia Rodarte-Quayle",
+			Bio:      "Head of logistics",
+			Email:    "lydia@madrigal.com",
+			ImportID: "lydia",
+		},
+	}
+
+	// Register the speaker in the CFP API outside of the cluster, with the
+	// same fields as the Speaker
+	existing := obj.DeepCopy()
+	existing.Status.ID = obj.Spec.ImportID
+	cfpClient, err := cfp.NewClient(os.Getenv("CFP_API_ENDPOINT"), http.DefaultClient)
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = cfpClient.Speakers().Create(ctx, speakerRecord(existing, ""))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(testEnv.CreateAndWait(ctx, obj)).To(Succeed())
+
+	key := client.ObjectKeyFromObject(obj)
+	// Wait for Speaker to be Ready
+	g.Eventually(func() bool {
+		if err := testEnv.Get(ctx, key, obj); err != nil {
+			return false
+		}
+		return conditions.IsReady(obj) && obj.Generation == obj.Status.ObservedGeneration
+	}, timeout).Should(BeTrue())
+	g.Expect(obj.Status.ID).To(Equal(obj.Spec.ImportID))
+
+	// The adopted record carries the owner marker of the Speaker
+	remote, err := cfpClient.Speakers().Get(ctx, obj.Status.ID)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(remote.Owner).To(Equal(ownerMarker("test-cluster", obj)))
+}
+
+func Test_Speaker_AdoptOwnRecord(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	ns, err := testEnv.CreateNamespace(ctx, "speaker-ns")
+	g.Expect(err).NotTo(HaveOccurred())
+	defer func() {
+		g.Expect(testEnv.Delete(ctx, ns)).To(Succeed())
+	}()
+
+	// Create a suspended Speaker, so that its record can be created as if its
+	// status had been lost after the creation
+	obj := &talksv1.Speaker{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "speaker",
+			Namespace: ns.Name,
+		},
+		Spec: talksv1.SpeakerSpec{
+			Name:    "Mike Ehrmantraut",
+			Bio:     "Head of security",
+			Email:   "mike@lospolloshermanos.com",
+			Suspend: true,
+		},
+	}
+	g.Expect(testEnv.CreateAndWait(ctx, obj)).To(Succeed())
+
+	cfpClient, err := cfp.NewClient(os.Getenv("CFP_API_ENDPOINT"), http.DefaultClient)
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = cfpClient.Speakers().Create(ctx, speakerRecord(obj, ownerMarker("test-cluster", obj)))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Resume the reconciliation
+	key := client.ObjectKeyFromObject(obj)
+	g.Expect(testEnv.Get(ctx, key, obj)).To(Succeed())
+	patchHelper, err := patch.NewHelper(obj, testEnv)
+	g.Expect(err).ToNot(HaveOccurred())
+	obj.Spec.Suspend = false
+	g.Expect(patchHelper.Patch(ctx, obj)).To(Succeed())
+
+	// Wait for Speaker to be Ready, its own record is adopted
+	g.Eventually(func() bool {
+		if err := testEnv.Get(ctx, key, obj); err != nil {
+			return false
+		}
+		return conditions.IsReady(obj) && obj.Generation == obj.Status.ObservedGeneration
+	}, timeout).Should(BeTrue())
+	g.Expect(conditions.Has(obj, meta.StalledCondition)).To(BeFalse())
+	g.Expect(obj.Status.ID).To(Equal(fmt.Sprintf("%s-%s", obj.Namespace, obj.Name)))
+}
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"k8s.io/apimachinery/pkg/api/equality"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
@@ -35,6 +36,16 @@ func (SpeakerChangePredicate) Update(e event.UpdateEvent) bool {
 		return true
 	}
 
+	// take action if the speaker is being deleted
+	if oldSource.DeletionTimestamp.IsZero() && !newSource.DeletionTimestamp.IsZero() {
+		return true
+	}
+
+	// take action if the namespaces allowed to reference the speaker changed
+	if newSource.Status.ID != "" && !equality.Semantic.DeepEqual(oldSource.Spec.AccessFrom, newSource.Spec.AccessFrom) {
+		return true
+	}
+
 	return false
 }
 
@@ -33,6 +33,7 @@ import (
 	//+kubebuilder:scaffold:imports
 
 	talksv1 "github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/api/v1"
+	talksv2 "github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/api/v2"
 )
 
 // These tests use Ginkgo (BDD-style Go testing framework). Refer to
@@ -43,10 +44,14 @@ const timeout = 20 * time.Second
 var (
 	testEnv *testenv.Environment
 	ctx     = ctrl.SetupSignalHandler()
+
+	speakerValidator  *talksv1.SpeakerValidator
+	proposalValidator *talksv1.ProposalValidator
 )
 
 func TestMain(m *testing.M) {
 	utilruntime.Must(talksv1.AddToScheme(scheme.Scheme))
+	utilruntime.Must(talksv2.AddToScheme(scheme.Scheme))
 
 	testEnv = testenv.New(testenv.WithCRDPath(filepath.Join("..", "config", "crd", "bases")))
 
@@ -55,21 +60,40 @@ func TestMain(m *testing.M) {
 	cfpAPI := os.Getenv("CFP_API_ENDPOINT")
 
 	if err := (&SpeakerReconciler{
-		Client:     testEnv.Client,
-		HTTPClient: client,
-		CfpAPI:     cfpAPI,
+		Client:        testEnv.Client,
+		HTTPClient:    client,
+		EventRecorder: NewDedupEventRecorder(testEnv.GetEventRecorderFor("speaker-controller"), time.Minute),
+		CfpAPI:        cfpAPI,
+		ClusterID:     "test-cluster",
 	}).SetupWithManager(testEnv.Manager); err != nil {
 		panic(err)
 	}
 
 	if err := (&ProposalReconciler{
-		Client:     testEnv.Client,
-		HTTPClient: client,
-		CfpAPI:     cfpAPI,
+		Client:        testEnv.Client,
+		HTTPClient:    client,
+		EventRecorder: NewDedupEventRecorder(testEnv.GetEventRecorderFor("proposal-controller"), time.Minute),
+		CfpAPI:        cfpAPI,
+		MinReviews:    2,
+		ClusterID:     "test-cluster",
+	}).SetupWithManager(testEnv.Manager); err != nil {
+		panic(err)
+	}
+
+	if err := (&ReviewReconciler{
+		Client:        testEnv.Client,
+		HTTPClient:    client,
+		EventRecorder: NewDedupEventRecorder(testEnv.GetEventRecorderFor("review-controller"), time.Minute),
+		CfpAPI:        cfpAPI,
 	}).SetupWithManager(testEnv.Manager); err != nil {
 		panic(err)
 	}
 
+	// The test environment does not serve the webhooks, the validators are
+	// called directly against its API server instead.
+	speakerValidator = &talksv1.SpeakerValidator{Client: testEnv.Client}
+	proposalValidator = &talksv1.ProposalValidator{Client: testEnv.Client}
+
 	go func() {
 		fmt.Println("Starting the test environment")
 		if err := testEnv.Start(ctx); err != nil {
@@ -0,0 +1,200 @@
+package controllers
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	. "github.com/onsi/gomega"
+	talksv1 "github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/api/v1"
+)
+
+func Test_Speaker_Validation(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	ns, err := testEnv.CreateNamespace(ctx, "speaker-webhook-ns")
+	g.Expect(err).NotTo(HaveOccurred())
+	defer func() {
+		g.Expect(testEnv.Delete(ctx, ns)).To(Succeed())
+	}()
+
+	existing := &talksv1.Speaker{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "speaker",
+			Namespace: ns.Name,
+		},
+		Spec: talksv1.SpeakerSpec{
+			Name:  "test",
+			Email: "already.used@gmail.com",
+		},
+	}
+	g.Expect(testEnv.CreateAndWait(ctx, existing)).To(Succeed())
+
+	testCases := []struct {
+		name    string
+		email   string
+		wantErr bool
+	}{
+		{
+			name:    "test speaker with a new email is accepted",
+			email:   "new.speaker@gmail.com",
+			wantErr: false,
+		},
+		{
+			name:    "test speaker with an email used by another speaker is rejected",
+			email:   "Already.Used@gmail.com",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := &talksv1.Speaker{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "new-speaker",
+					Namespace: ns.Name,
+				},
+				Spec: talksv1.SpeakerSpec{
+					Name:  "new",
+					Email: tc.email,
+				},
+			}
+
+			// The validator reads from the cache, wait for it to be in sync
+			g.Eventually(func() bool {
+				err := speakerValidator.ValidateCreate(ctx, obj)
+				return (err != nil) == tc.wantErr
+			}, timeout).Should(BeTrue())
+		})
+	}
+
+	// Updating the existing speaker without changing its email is accepted
+	g.Expect(speakerValidator.ValidateUpdate(ctx, existing, existing.DeepCopy())).To(Succeed())
+
+	// A speaker referencing an unknown conference is rejected
+	unknownConference := existing.DeepCopy()
+	unknownConference.Spec.ConferenceRef = &talksv1.ConferenceRef{Name: "unknown"}
+	g.Expect(speakerValidator.ValidateCreate(ctx, unknownConference)).To(MatchError(ContainSubstring("spec.conferenceRef")))
+
+	// The conference of a speaker recorded in the CFP API can not change
+	recorded := existing.DeepCopy()
+	recorded.Status.ID = "speaker-id"
+	moved := recorded.DeepCopy()
+	moved.Spec.ConferenceRef = &talksv1.ConferenceRef{Name: "unknown"}
+	g.Expect(speakerValidator.ValidateUpdate(ctx, recorded, moved)).To(MatchError(ContainSubstring("can no longer change conference")))
+}
+
+func Test_Proposal_Validation(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	ns, err := testEnv.CreateNamespace(ctx, "proposal-webhook-ns")
+	g.Expect(err).NotTo(HaveOccurred())
+	defer func() {
+		g.Expect(testEnv.Delete(ctx, ns)).To(Succeed())
+	}()
+
+	speaker := &talksv1.Speaker{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "speaker",
+			Namespace: ns.Name,
+		},
+		Spec: talksv1.SpeakerSpec{
+			Name:  "test",
+			Email: "webhook.speaker@gmail.com",
+		},
+	}
+	g.Expect(testEnv.CreateAndWait(ctx, speaker)).To(Succeed())
+	g.Eventually(func() bool {
+		return testEnv.Get(ctx, client.ObjectKeyFromObject(speaker), speaker) == nil
+	}, timeout).Should(BeTrue())
+
+	newProposal := func(speakerName, proposalType, submission string) *talksv1.Proposal {
+		return &talksv1.Proposal{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "proposal",
+				Namespace: ns.Name,
+			},
+			Spec: talksv1.ProposalSpec{
+				Title:      "this is a test proposal",
+				Abstract:   "this is a test abstract",
+				Type:       proposalType,
+				SpeakerRef: &talksv1.SpeakerRef{Name: speakerName},
+			},
+			Status: talksv1.ProposalStatus{
+				Submission: submission,
+			},
+		}
+	}
+
+	testCases := []struct {
+		name    string
+		old     *talksv1.Proposal
+		obj     *talksv1.Proposal
+		wantErr bool
+	}{
+		{
+			name:    "test create proposal with an existing speaker is accepted",
+			obj:     newProposal(speaker.Name, "talk", ""),
+			wantErr: false,
+		},
+		{
+			name:    "test create proposal with an unknown speaker is rejected",
+			obj:     newProposal("unknown", "talk", ""),
+			wantErr: true,
+		},
+		{
+			name:    "test create proposal with a type not accepted by the CFP API is rejected",
+			obj:     newProposal(speaker.Name, "keynote", ""),
+			wantErr: true,
+		},
+		{
+			name:    "test update draft proposal is accepted",
+			old:     newProposal(speaker.Name, "talk", talksv1.ProposalStateDraft),
+			obj:     newProposal(speaker.Name, "lightning", talksv1.ProposalStateDraft),
+			wantErr: false,
+		},
+		{
+			name:    "test update final proposal is rejected",
+			old:     newProposal(speaker.Name, "talk", talksv1.ProposalStateFinal),
+			obj:     newProposal(speaker.Name, "lightning", talksv1.ProposalStateFinal),
+			wantErr: true,
+		},
+		{
+			name: "test update final proposal metadata is accepted",
+			old:  newProposal(speaker.Name, "talk", talksv1.ProposalStateFinal),
+			obj: func() *talksv1.Proposal {
+				p := newProposal(speaker.Name, "talk", talksv1.ProposalStateFinal)
+				p.Labels = map[string]string{"track": "operations"}
+				return p
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "test suspend final proposal is accepted",
+			old:  newProposal(speaker.Name, "talk", talksv1.ProposalStateFinal),
+			obj: func() *talksv1.Proposal {
+				p := newProposal(speaker.Name, "talk", talksv1.ProposalStateFinal)
+				p.Spec.Suspend = true
+				return p
+			}(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var err error
+			if tc.old != nil {
+				err = proposalValidator.ValidateUpdate(ctx, tc.old, tc.obj)
+			} else {
+				err = proposalValidator.ValidateCreate(ctx, tc.obj)
+			}
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
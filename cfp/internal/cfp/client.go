@@ -3,10 +3,16 @@ package cfp
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
+
+	"github.com/scottrigby/cfp-api/pkg/types"
 )
 
 const (
@@ -14,9 +20,19 @@ const (
 	ProposalPath = "/api/proposals"
 )
 
+// ReviewPath returns the path of the reviews of the proposal with the given ID.
+func ReviewPath(proposalID string) string {
+	return fmt.Sprintf("%s/%s/reviews", ProposalPath, proposalID)
+}
+
 type Client struct {
-	Client   *http.Client
-	endpoint string
+	Client *http.Client
+	// Limiter limits the requests sent to the endpoint, if set.
+	Limiter *RateLimiter
+	// Credentials authenticate the requests sent to the endpoint, if set and
+	// the endpoint is the one of the credentials.
+	Credentials *CredentialStore
+	endpoint    string
 }
 
 func NewClient(endpoint string, client *http.Client) (*Client, error) {
@@ -35,71 +51,116 @@ func NewClient(endpoint string, client *http.Client) (*Client, error) {
 	}, nil
 }
 
-func (c *Client) Create(ctx context.Context, path string, body []byte) ([]byte, error) {
+// Endpoint returns the address of the CFP API the client sends requests to.
+func (c *Client) Endpoint() string {
+	return c.endpoint
+}
+
+// Create creates a record at path, and returns it as recorded with its
+// entity tag.
+func (c *Client) Create(ctx context.Context, path string, body []byte) ([]byte, string, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s%s", c.endpoint, path), bytes.NewBuffer(body))
 	if err != nil {
-		return nil, &Error{Reason: ErrCreateRequest, Err: err}
+		return nil, "", &Error{Reason: ErrCreateRequest, Err: err}
 	}
 
-	resp, err := c.Client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
-		return nil, &Error{Reason: ErrMakeRequest, Err: err}
+		return nil, "", &Error{Reason: ErrMakeRequest, Err: err}
 	}
 
 	defer resp.Body.Close()
 	payload, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, computeError(fmt.Errorf("error reading response: %w", err), path, http.MethodGet)
+		return nil, "", computeError(fmt.Errorf("error reading response: %w", err), path, http.MethodGet)
+	}
+
+	// A record with the same ID exists, which is not an error of the request
+	// itself but of the ID it creates
+	if resp.StatusCode == http.StatusConflict {
+		e := responseError(resp, payload, "create", path, http.MethodPost)
+		e.Reason = ErrAlreadyExists
+		return nil, "", e
 	}
 
 	// return the response body which will contain important error info
 	if resp.StatusCode != http.StatusOK {
-		return nil, computeError(fmt.Errorf("create error: %s: %s", payload, resp.Status), path, http.MethodPost)
+		return nil, "", responseError(resp, payload, "create", path, http.MethodPost)
 	}
 
-	return payload, nil
+	return payload, resp.Header.Get("ETag"), nil
 }
 
-func (c *Client) Update(ctx context.Context, path, id string, body []byte) ([]byte, error) {
+// Update replaces the record with the given ID at path, and returns it as
+// recorded with its new entity tag. If etag is set, the record is only
+// replaced if it still has this entity tag, and the update fails with a 412
+// status otherwise.
+func (c *Client) Update(ctx context.Context, path, id, etag string, body []byte) ([]byte, string, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fmt.Sprintf("%s%s/%s", c.endpoint, path, id), bytes.NewBuffer(body))
 
 	if err != nil {
-		return nil, &Error{Reason: ErrCreateRequest, Err: err}
+		return nil, "", &Error{Reason: ErrCreateRequest, Err: err}
+	}
+	if etag != "" {
+		req.Header.Set("If-Match", etag)
 	}
 
-	resp, err := c.Client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
-		return nil, &Error{Reason: ErrMakeRequest, Err: err}
+		return nil, "", &Error{Reason: ErrMakeRequest, Err: err}
 	}
 
 	defer resp.Body.Close()
 	payload, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, computeError(fmt.Errorf("error reading response: %w", err), path, http.MethodGet)
+		return nil, "", computeError(fmt.Errorf("error reading response: %w", err), path, http.MethodGet)
 	}
 
 	// return the response body which will contain important error info
 	if resp.StatusCode != http.StatusOK {
-		return nil, computeError(fmt.Errorf("update error: %s: %s", payload, resp.Status), path, http.MethodPut)
+		return nil, "", responseError(resp, payload, "update", path, http.MethodPut)
 	}
 
-	return payload, nil
+	return payload, resp.Header.Get("ETag"), nil
 }
 
-func (c *Client) Get(ctx context.Context, path, id string) ([]byte, error) {
+// Get returns the record with the given ID at path, with its entity tag.
+func (c *Client) Get(ctx context.Context, path, id string) ([]byte, string, error) {
 	// Get by id
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s%s/%s", c.endpoint, path, id), nil)
 	if err != nil {
-		return nil, &Error{Reason: ErrCreateRequest, Err: err}
+		return nil, "", &Error{Reason: ErrCreateRequest, Err: err}
 	}
 
-	resp, err := c.Client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
-		return nil, &Error{Reason: ErrMakeRequest, Err: err}
+		return nil, "", &Error{Reason: ErrMakeRequest, Err: err}
+	}
+
+	defer resp.Body.Close()
+	payload, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", computeError(fmt.Errorf("error reading response: %w", err), path, http.MethodGet)
 	}
 
+	// return the response body which will contain important error info
 	if resp.StatusCode != http.StatusOK {
-		return nil, computeError(fmt.Errorf("get error: %s", resp.Status), path, http.MethodGet)
+		return nil, "", responseError(resp, payload, "get", path, http.MethodGet)
+	}
+
+	return payload, resp.Header.Get("ETag"), nil
+}
+
+// List returns the records at path, filtered and paginated by the query.
+func (c *Client) List(ctx context.Context, path string, query url.Values) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s%s?%s", c.endpoint, path, query.Encode()), nil)
+	if err != nil {
+		return nil, &Error{Reason: ErrCreateRequest, Err: err}
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, &Error{Reason: ErrMakeRequest, Err: err}
 	}
 
 	defer resp.Body.Close()
@@ -110,7 +171,7 @@ func (c *Client) Get(ctx context.Context, path, id string) ([]byte, error) {
 
 	// return the response body which will contain important error info
 	if resp.StatusCode != http.StatusOK {
-		return nil, computeError(fmt.Errorf("get error: %s: %s", payload, resp.Status), path, http.MethodGet)
+		return nil, responseError(resp, payload, "list", path, http.MethodGet)
 	}
 
 	return payload, nil
@@ -122,24 +183,102 @@ func (c *Client) Delete(ctx context.Context, path, id string) error {
 		return &Error{Reason: ErrCreateRequest, Err: err}
 	}
 
-	resp, err := c.Client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return &Error{Reason: ErrMakeRequest, Err: err}
 	}
 
+	defer resp.Body.Close()
+	payload, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return computeError(fmt.Errorf("error reading response: %w", err), path, http.MethodDelete)
+	}
+
 	if resp.StatusCode != http.StatusOK {
-		return computeError(fmt.Errorf("error deleting speaker: %s", resp.Status), path, http.MethodDelete)
+		return responseError(resp, payload, "delete", path, http.MethodDelete)
 	}
 
 	return nil
 }
 
+// do sends an authenticated request once the rate limiter of the endpoint
+// allows it, within a span propagated to the endpoint.
+func (c *Client) do(req *http.Request) (resp *http.Response, err error) {
+	req, span := startSpan(req)
+	defer func() { endSpan(span, resp, err) }()
+
+	if c.Credentials != nil {
+		c.Credentials.authenticate(req, c.endpoint)
+	}
+	if c.Limiter != nil {
+		if err := c.Limiter.Wait(req.Context(), c.endpoint); err != nil {
+			return nil, fmt.Errorf("rate limiter: %w", err)
+		}
+	}
+
+	start := time.Now()
+	resp, err = c.Client.Do(req)
+	recordRequest(req, resp, err, start)
+	return resp, err
+}
+
+// responseError returns the error of the op request of a response with an
+// unexpected status, carrying its status code and Retry-After delay, and the
+// error code and field errors of its problem details if any.
+func responseError(resp *http.Response, payload []byte, op string, path string, method string) *Error {
+	detail := strings.TrimSpace(string(payload))
+	problem := decodeProblem(resp, payload)
+	if problem != nil {
+		detail = problem.Detail
+		if detail == "" {
+			detail = problem.Title
+		}
+	}
+
+	e, ok := computeError(fmt.Errorf("%s error: %s: %s", op, detail, resp.Status), path, method).(*Error)
+	if !ok {
+		e = &Error{Reason: ErrUnknown, Err: fmt.Errorf("%s error: %s", op, resp.Status)}
+	}
+	e.StatusCode = resp.StatusCode
+	e.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	if problem != nil {
+		e.Code = problem.Code
+		e.FieldErrors = problem.Errors
+	}
+	return e
+}
+
+// decodeProblem returns the RFC 7807 problem details of a response, or nil if
+// its body is not an application/problem+json document.
+func decodeProblem(resp *http.Response, payload []byte) *types.Problem {
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || mediaType != types.ProblemContentType {
+		return nil
+	}
+
+	problem := &types.Problem{}
+	if err := json.Unmarshal(payload, problem); err != nil {
+		return nil
+	}
+	return problem
+}
+
 func computeError(err error, path string, method string) error {
 	if err == nil {
 		return nil
 	}
 
+	isReviewPath := strings.HasPrefix(path, ProposalPath+"/") && strings.HasSuffix(path, "/reviews")
+
 	switch {
+	case method == http.MethodGet && isReviewPath:
+		return &Error{Reason: ErrFetchReview, Err: err}
+	case method == http.MethodPost && isReviewPath:
+		return &Error{Reason: ErrCreateReview, Err: err}
+	case method == http.MethodPut && isReviewPath:
+		return &Error{Reason: ErrUpdateReview, Err: err}
+	case method == http.MethodDelete && isReviewPath:
+		return &Error{Reason: ErrDeleteReview, Err: err}
 	case method == http.MethodGet && path == SpeakerPath:
 		return &Error{Reason: ErrFetchSpeaker, Err: err}
 	case method == http.MethodGet && path == ProposalPath:
@@ -0,0 +1,59 @@
+package cfp
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Credentials authenticate the requests to the CFP API, with a bearer token
+// or a username and password for basic authentication.
+type Credentials struct {
+	Token    string
+	Username string
+	Password string
+}
+
+// CredentialStore holds the credentials of the CFP API. It is shared by the
+// clients of all the reconcilers, so that rotated credentials are used by the
+// following requests without restarting the controller.
+type CredentialStore struct {
+	// Endpoint is the address of the CFP API the credentials belong to, they
+	// are not sent to any other endpoint.
+	Endpoint string
+
+	mu    sync.RWMutex
+	creds Credentials
+}
+
+// Set replaces the credentials of the store.
+func (s *CredentialStore) Set(creds Credentials) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.creds = creds
+}
+
+// Get returns the credentials of the store.
+func (s *CredentialStore) Get() Credentials {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.creds
+}
+
+// authenticate sets the Authorization header of a request to the endpoint
+// from the credentials of the store, the token being preferred to basic
+// authentication. Requests to other endpoints than the one of the store are
+// left unauthenticated.
+func (s *CredentialStore) authenticate(req *http.Request, endpoint string) {
+	if strings.TrimSuffix(endpoint, "/") != strings.TrimSuffix(s.Endpoint, "/") {
+		return
+	}
+
+	creds := s.Get()
+	switch {
+	case creds.Token != "":
+		req.Header.Set("Authorization", "Bearer "+creds.Token)
+	case creds.Username != "":
+		req.SetBasicAuth(creds.Username, creds.Password)
+	}
+}
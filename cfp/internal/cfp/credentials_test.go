@@ -0,0 +1,64 @@
+package cfp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_CredentialStore(t *testing.T) {
+	g := NewWithT(t)
+
+	var header string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("Authorization")
+		w.Write([]byte(`{"id":"ns-speaker"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, server.Client())
+	g.Expect(err).ToNot(HaveOccurred())
+	client.Credentials = &CredentialStore{Endpoint: server.URL}
+
+	_, err = client.Speakers().Get(context.Background(), "ns-speaker")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(header).To(BeEmpty())
+
+	client.Credentials.Set(Credentials{Token: "secret-token"})
+	_, err = client.Speakers().Get(context.Background(), "ns-speaker")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(header).To(Equal("Bearer secret-token"))
+
+	// Rotated credentials are used by the following requests
+	client.Credentials.Set(Credentials{Username: "user", Password: "pass"})
+	_, err = client.Speakers().Get(context.Background(), "ns-speaker")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(header).To(Equal("Basic dXNlcjpwYXNz"))
+}
+
+func Test_CredentialStore_OtherEndpoint(t *testing.T) {
+	g := NewWithT(t)
+
+	var header string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("Authorization")
+		w.Write([]byte(`{"id":"ns-speaker"}`))
+	}))
+	defer server.Close()
+
+	// The credentials of the CFP API of the controller are not sent to the
+	// endpoint of a conference
+	credentials := &CredentialStore{Endpoint: "http://cfp-api:50001"}
+	credentials.Set(Credentials{Token: "secret-token"})
+
+	client, err := NewClient(server.URL, server.Client())
+	g.Expect(err).ToNot(HaveOccurred())
+	client.Credentials = credentials
+
+	_, err = client.Speakers().Get(context.Background(), "ns-speaker")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(header).To(BeEmpty())
+}
@@ -3,6 +3,11 @@ package cfp
 import (
 	"errors"
 	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/scottrigby/cfp-api/pkg/types"
 )
 
 type ErrorReason struct {
@@ -17,6 +22,18 @@ func (e ErrorReason) Error() string {
 type Error struct {
 	Reason ErrorReason
 	Err    error
+
+	// StatusCode is the HTTP status code of the response which caused the
+	// error, if any.
+	StatusCode int
+	// RetryAfter is the delay requested by the Retry-After header of the
+	// response, if any.
+	RetryAfter time.Duration
+	// Code is the machine-readable error code of the problem details of the
+	// response, if any, e.g. types.CodeValidationFailed.
+	Code string
+	// FieldErrors are the fields of the request the CFP API rejected, if any.
+	FieldErrors []types.FieldError
 }
 
 func (e *Error) Error() string {
@@ -45,5 +62,91 @@ var (
 	ErrUpdateProposal = ErrorReason{Reason: "UpdateProposalFailed", Summary: "error updating proposal"}
 	ErrFetchProposal  = ErrorReason{Reason: "FetchProposalFailed", Summary: "error fetching proposal"}
 	ErrDeleteProposal = ErrorReason{Reason: "DeleteProposalFailed", Summary: "error deleting proposal"}
+	ErrCreateReview   = ErrorReason{Reason: "CreateReviewFailed", Summary: "error creating review"}
+	ErrUpdateReview   = ErrorReason{Reason: "UpdateReviewFailed", Summary: "error updating review"}
+	ErrFetchReview    = ErrorReason{Reason: "FetchReviewFailed", Summary: "error fetching review"}
+	ErrDeleteReview   = ErrorReason{Reason: "DeleteReviewFailed", Summary: "error deleting review"}
+	ErrAlreadyExists  = ErrorReason{Reason: "AlreadyExists", Summary: "record already exists"}
 	ErrUnknown        = ErrorReason{Reason: "Unknown", Summary: "unknown error"}
 )
+
+// IsTerminal returns true if err is caused by a request the CFP API rejected
+// as invalid, which fails again until it is changed. Requests rejected for
+// their credentials are not terminal, as they succeed once the credentials
+// are fixed.
+func IsTerminal(err error) bool {
+	var e *Error
+	if !errors.As(err, &e) {
+		return false
+	}
+
+	switch e.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusRequestTimeout,
+		http.StatusConflict, http.StatusPreconditionFailed, http.StatusTooManyRequests:
+		return false
+	}
+	return e.StatusCode >= 400 && e.StatusCode < 500
+}
+
+// IsNotFound returns true if err is caused by the CFP API not finding the
+// record of the request, or one it refers to.
+func IsNotFound(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && (e.Code == types.CodeNotFound || e.StatusCode == http.StatusNotFound)
+}
+
+// IsConflict returns true if err is caused by the CFP API already having a
+// record with the ID of the one to create.
+func IsConflict(err error) bool {
+	var e *Error
+	if !errors.As(err, &e) {
+		return false
+	}
+	return e.Code == types.CodeAlreadyExists || e.StatusCode == http.StatusConflict || e.Reason == ErrAlreadyExists
+}
+
+// IsValidation returns true if err is caused by the CFP API rejecting the
+// fields of the record of the request, which are listed in its FieldErrors.
+func IsValidation(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.Code == types.CodeValidationFailed
+}
+
+// IsPreconditionFailed returns true if err is caused by the CFP API rejecting
+// an update because the record has been modified since it was read, in which
+// case the record must be read again before updating it.
+func IsPreconditionFailed(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.StatusCode == http.StatusPreconditionFailed
+}
+
+// IsThrottled returns true if err is caused by the CFP API throttling the
+// requests or being unavailable, with the delay after which the request can be
+// retried. The delay is zero if the response did not request one.
+func IsThrottled(err error) (time.Duration, bool) {
+	var e *Error
+	if !errors.As(err, &e) {
+		return 0, false
+	}
+
+	switch e.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return e.RetryAfter, true
+	}
+	return 0, false
+}
+
+// parseRetryAfter returns the delay of a Retry-After header, given either in
+// seconds or as an HTTP date, or zero if it is missing or invalid.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
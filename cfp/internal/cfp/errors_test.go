@@ -0,0 +1,32 @@
+package cfp
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_parseRetryAfter(t *testing.T) {
+	now := time.Date(2022, 10, 24, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{name: "missing", value: "", want: 0},
+		{name: "seconds", value: "120", want: 2 * time.Minute},
+		{name: "HTTP date", value: now.Add(time.Minute).Format(http.TimeFormat), want: time.Minute},
+		{name: "past HTTP date", value: now.Add(-time.Minute).Format(http.TimeFormat), want: 0},
+		{name: "invalid", value: "soon", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(parseRetryAfter(tt.value, now)).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package cfp
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// rateLimiterWait observes the time requests waited for the rate limiter of
+// their endpoint before being sent.
+var rateLimiterWait = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "cfp_client_rate_limiter_wait_seconds",
+		Help:    "Time requests to the CFP API waited for the rate limiter of their endpoint.",
+		Buckets: []float64{0.001, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	},
+	[]string{"endpoint"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(rateLimiterWait)
+}
+
+// RateLimiter limits the requests sent to each CFP API endpoint with a token
+// bucket of its own. It is shared by the clients of all the reconcilers, so
+// that they do not flood an endpoint together.
+// The endpoints are set when creating it, so that the buckets and the
+// endpoint label of the metric are bounded by the configured endpoints.
+type RateLimiter struct {
+	limiters map[string]*rate.Limiter
+}
+
+// NewRateLimiter returns a RateLimiter allowing limit.QPS requests per
+// second to each of the endpoints, with bursts of up to limit.Burst requests,
+// unless overridden by the limit of the endpoint in overrides. A QPS lower
+// than or equal to zero does not limit the requests. The overrides must be
+// limits of the given endpoints.
+func NewRateLimiter(limit EndpointLimit, overrides map[string]EndpointLimit, endpoints ...string) (*RateLimiter, error) {
+	limiters := make(map[string]*rate.Limiter, len(endpoints))
+	for _, endpoint := range endpoints {
+		limiters[strings.TrimSuffix(endpoint, "/")] = limit.newLimiter()
+	}
+	for endpoint, l := range overrides {
+		endpoint = strings.TrimSuffix(endpoint, "/")
+		if _, ok := limiters[endpoint]; !ok {
+			return nil, fmt.Errorf("unknown CFP API endpoint '%s'", endpoint)
+		}
+		limiters[endpoint] = l.newLimiter()
+	}
+	return &RateLimiter{limiters: limiters}, nil
+}
+
+// EndpointLimit is the rate limit of the requests sent to an endpoint.
+type EndpointLimit struct {
+	QPS   float64
+	Burst int
+}
+
+// newLimiter returns the token bucket of the limit, with bursts of at least
+// one request.
+func (l EndpointLimit) newLimiter() *rate.Limiter {
+	limit := rate.Limit(l.QPS)
+	if l.QPS <= 0 {
+		limit = rate.Inf
+	}
+	burst := l.Burst
+	if burst < 1 {
+		burst = 1
+	}
+	return rate.NewLimiter(limit, burst)
+}
+
+// ParseEndpointLimits parses a comma-separated list of endpoint=qps:burst
+// entries, e.g. "https://cfp.kubecon.eu=5:10".
+func ParseEndpointLimits(s string) (map[string]EndpointLimit, error) {
+	limits := make(map[string]EndpointLimit)
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		// The endpoint may contain colons, the limit is after the last '='
+		i := strings.LastIndex(entry, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid endpoint limit '%s', want endpoint=qps:burst", entry)
+		}
+		endpoint, limit := entry[:i], entry[i+1:]
+		qps, burst, ok := strings.Cut(limit, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid endpoint limit '%s', want endpoint=qps:burst", entry)
+		}
+		var (
+			l   EndpointLimit
+			err error
+		)
+		if l.QPS, err = strconv.ParseFloat(qps, 64); err != nil {
+			return nil, fmt.Errorf("invalid qps of endpoint limit '%s': %w", entry, err)
+		}
+		if l.Burst, err = strconv.Atoi(burst); err != nil {
+			return nil, fmt.Errorf("invalid burst of endpoint limit '%s': %w", entry, err)
+		}
+		limits[strings.TrimSuffix(endpoint, "/")] = l
+	}
+	return limits, nil
+}
+
+// Wait blocks until a request can be sent to the endpoint, or returns an
+// error if ctx is done first. Requests to an endpoint the RateLimiter was
+// not created with are refused.
+func (r *RateLimiter) Wait(ctx context.Context, endpoint string) error {
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	l, ok := r.limiters[endpoint]
+	if !ok {
+		return fmt.Errorf("unknown CFP API endpoint '%s'", endpoint)
+	}
+
+	start := time.Now()
+	err := l.Wait(ctx)
+	rateLimiterWait.WithLabelValues(endpoint).Observe(time.Since(start).Seconds())
+	return err
+}
@@ -0,0 +1,120 @@
+package cfp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_RateLimiter(t *testing.T) {
+	g := NewWithT(t)
+
+	limiter, err := NewRateLimiter(EndpointLimit{QPS: 0.001, Burst: 2}, nil, "http://cfp-a", "http://cfp-b/")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The burst is allowed at once
+	g.Expect(limiter.Wait(context.Background(), "http://cfp-a")).To(Succeed())
+	g.Expect(limiter.Wait(context.Background(), "http://cfp-a")).To(Succeed())
+
+	// Further requests wait until the context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	g.Expect(limiter.Wait(ctx, "http://cfp-a")).ToNot(Succeed())
+
+	// Each endpoint has a bucket of its own
+	g.Expect(limiter.Wait(context.Background(), "http://cfp-b")).To(Succeed())
+
+	// Requests to other endpoints are refused
+	g.Expect(limiter.Wait(context.Background(), "http://cfp-c")).To(MatchError(ContainSubstring("unknown CFP API endpoint 'http://cfp-c'")))
+
+	// A limiter without QPS does not limit the requests
+	unlimited, err := NewRateLimiter(EndpointLimit{}, nil, "http://cfp-a")
+	g.Expect(err).ToNot(HaveOccurred())
+	for i := 0; i < 10; i++ {
+		g.Expect(unlimited.Wait(ctx, "http://cfp-a")).To(Succeed())
+	}
+}
+
+func Test_RateLimiter_Overrides(t *testing.T) {
+	g := NewWithT(t)
+
+	overrides := map[string]EndpointLimit{"http://cfp-b/": {QPS: 0.001, Burst: 3}}
+	limiter, err := NewRateLimiter(EndpointLimit{QPS: 0.001, Burst: 1}, overrides, "http://cfp-a", "http://cfp-b")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// The endpoint without override keeps the default burst
+	g.Expect(limiter.Wait(ctx, "http://cfp-a")).To(Succeed())
+	g.Expect(limiter.Wait(ctx, "http://cfp-a")).ToNot(Succeed())
+
+	// The overridden endpoint allows its own burst
+	for i := 0; i < 3; i++ {
+		g.Expect(limiter.Wait(ctx, "http://cfp-b")).To(Succeed())
+	}
+	g.Expect(limiter.Wait(ctx, "http://cfp-b")).ToNot(Succeed())
+
+	// Only the endpoints of the limiter can be overridden
+	overrides = map[string]EndpointLimit{"http://cfp-c": {QPS: 1, Burst: 1}}
+	_, err = NewRateLimiter(EndpointLimit{QPS: 1, Burst: 1}, overrides, "http://cfp-a")
+	g.Expect(err).To(MatchError(ContainSubstring("unknown CFP API endpoint 'http://cfp-c'")))
+}
+
+func Test_ParseEndpointLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		limits  string
+		want    map[string]EndpointLimit
+		wantErr string
+	}{
+		{
+			name:   "empty",
+			limits: "",
+			want:   map[string]EndpointLimit{},
+		},
+		{
+			name:   "endpoints with ports",
+			limits: "http://cfp-api:50001=5:10, https://cfp.kubecon.eu/=0.5:1",
+			want: map[string]EndpointLimit{
+				"http://cfp-api:50001":   {QPS: 5, Burst: 10},
+				"https://cfp.kubecon.eu": {QPS: 0.5, Burst: 1},
+			},
+		},
+		{
+			name:    "missing limit",
+			limits:  "http://cfp-api:50001",
+			wantErr: "want endpoint=qps:burst",
+		},
+		{
+			name:    "missing burst",
+			limits:  "http://cfp-api:50001=5",
+			wantErr: "want endpoint=qps:burst",
+		},
+		{
+			name:    "invalid qps",
+			limits:  "http://cfp-api:50001=fast:10",
+			wantErr: "invalid qps",
+		},
+		{
+			name:    "invalid burst",
+			limits:  "http://cfp-api:50001=5:many",
+			wantErr: "invalid burst",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := ParseEndpointLimits(tt.limits)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package cfp
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// noStatusCode is the code label of the requests which got no response.
+const noStatusCode = "none"
+
+var (
+	// requestsTotal counts the requests sent to the CFP API.
+	requestsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cfp_client_requests_total",
+			Help: "Number of requests sent to the CFP API, by resource, method and status code.",
+		},
+		[]string{"resource", "method", "code"},
+	)
+	// requestErrorsTotal counts the requests to the CFP API which failed,
+	// either without a response or with an error status.
+	requestErrorsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cfp_client_request_errors_total",
+			Help: "Number of requests to the CFP API which failed, by resource, method and status code.",
+		},
+		[]string{"resource", "method", "code"},
+	)
+	// requestDuration observes the latency of the requests to the CFP API,
+	// excluding the time waited for the rate limiter.
+	requestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "cfp_client_request_duration_seconds",
+			Help:    "Latency of the requests sent to the CFP API, by resource, method and status code.",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"resource", "method", "code"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(requestsTotal, requestErrorsTotal, requestDuration)
+}
+
+// recordRequest records the metrics of a request sent at start, with its
+// response or error.
+func recordRequest(req *http.Request, resp *http.Response, err error, start time.Time) {
+	code := noStatusCode
+	if resp != nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	labels := prometheus.Labels{
+		"resource": resourceOf(req.URL.Path),
+		"method":   req.Method,
+		"code":     code,
+	}
+
+	requestsTotal.With(labels).Inc()
+	requestDuration.With(labels).Observe(time.Since(start).Seconds())
+	if err != nil || resp.StatusCode >= http.StatusBadRequest {
+		requestErrorsTotal.With(labels).Inc()
+	}
+}
+
+// resourceOf returns the resource of the path of a request, e.g. "speakers"
+// for /api/speakers/<id>, or "reviews" for the reviews of a proposal.
+func resourceOf(path string) string {
+	if i := strings.Index(path, "/api/"); i >= 0 {
+		path = path[i+len("/api/"):]
+	}
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) >= 3 && parts[0] == "proposals" {
+		return parts[2]
+	}
+	return parts[0]
+}
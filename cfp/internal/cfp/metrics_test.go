@@ -0,0 +1,48 @@
+package cfp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func Test_resourceOf(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(resourceOf("/api/speakers")).To(Equal("speakers"))
+	g.Expect(resourceOf("/api/speakers/ns-speaker")).To(Equal("speakers"))
+	g.Expect(resourceOf("/api/proposals/ns-talk")).To(Equal("proposals"))
+	g.Expect(resourceOf("/api/proposals/ns-talk/reviews/ns-review")).To(Equal("reviews"))
+	g.Expect(resourceOf("/cfp/api/proposals")).To(Equal("proposals"))
+}
+
+func Test_ClientMetrics(t *testing.T) {
+	g := NewWithT(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"id":"ns-speaker"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, server.Client())
+	g.Expect(err).ToNot(HaveOccurred())
+
+	getCount := testutil.ToFloat64(requestsTotal.WithLabelValues("speakers", http.MethodGet, "200"))
+	deleteErrors := testutil.ToFloat64(requestErrorsTotal.WithLabelValues("speakers", http.MethodDelete, "404"))
+
+	_, err = client.Speakers().Get(context.Background(), "ns-speaker")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(client.Speakers().Delete(context.Background(), "ns-speaker")).ToNot(Succeed())
+
+	g.Expect(testutil.ToFloat64(requestsTotal.WithLabelValues("speakers", http.MethodGet, "200"))).To(Equal(getCount + 1))
+	g.Expect(testutil.ToFloat64(requestErrorsTotal.WithLabelValues("speakers", http.MethodGet, "200"))).To(BeZero())
+	g.Expect(testutil.ToFloat64(requestErrorsTotal.WithLabelValues("speakers", http.MethodDelete, "404"))).To(Equal(deleteErrors + 1))
+}
@@ -0,0 +1,290 @@
+package cfp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/scottrigby/cfp-api/pkg/types"
+)
+
+// ListOptions paginates the records of a list, sorted by ID.
+type ListOptions struct {
+	// Limit is the maximum number of records of the page, all the records
+	// are listed if zero.
+	Limit int
+	// Continue is the cursor of the page, as returned with the previous page.
+	Continue string
+}
+
+func (o ListOptions) query() url.Values {
+	query := url.Values{}
+	if o.Limit > 0 {
+		query.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Continue != "" {
+		query.Set("continue", o.Continue)
+	}
+	return query
+}
+
+// ProposalListOptions filters and paginates the proposal records, each
+// filter being ignored if empty.
+type ProposalListOptions struct {
+	ListOptions
+	// SpeakerID selects the proposals presented by the speaker.
+	SpeakerID string
+	// Status selects the proposals with the submission status.
+	Status string
+	// Type selects the proposals with the type of talk.
+	Type string
+}
+
+func (o ProposalListOptions) query() url.Values {
+	query := o.ListOptions.query()
+	for key, value := range map[string]string{"speakerID": o.SpeakerID, "status": o.Status, "type": o.Type} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	return query
+}
+
+// SpeakerService manages the speaker records of the CFP API.
+type SpeakerService struct {
+	client *Client
+}
+
+// Speakers returns the service managing the speaker records.
+func (c *Client) Speakers() *SpeakerService {
+	return &SpeakerService{client: c}
+}
+
+// Get returns the speaker record with the given ID.
+func (s *SpeakerService) Get(ctx context.Context, id string) (*types.Speaker, error) {
+	payload, etag, err := s.client.Get(ctx, SpeakerPath, id)
+	if err != nil {
+		return nil, err
+	}
+	speaker, err := decode[types.Speaker](payload, SpeakerPath)
+	if err != nil {
+		return nil, err
+	}
+	speaker.ETag = etag
+	return speaker, nil
+}
+
+// List returns the page of speaker records selected by opts.
+func (s *SpeakerService) List(ctx context.Context, opts ListOptions) (*types.SpeakerList, error) {
+	payload, err := s.client.List(ctx, SpeakerPath, opts.query())
+	if err != nil {
+		return nil, err
+	}
+	return decode[types.SpeakerList](payload, SpeakerPath)
+}
+
+// Create creates the speaker record, and returns it as recorded.
+func (s *SpeakerService) Create(ctx context.Context, speaker *types.Speaker) (*types.Speaker, error) {
+	body, err := encode(speaker, SpeakerPath)
+	if err != nil {
+		return nil, err
+	}
+	payload, etag, err := s.client.Create(ctx, SpeakerPath, body)
+	if err != nil {
+		return nil, err
+	}
+	recorded, err := decode[types.Speaker](payload, SpeakerPath)
+	if err != nil {
+		return nil, err
+	}
+	recorded.ETag = etag
+	return recorded, nil
+}
+
+// Update replaces the speaker record with the ID of speaker, and returns it as
+// recorded. If the ETag of speaker is set, the update fails with a 412 status
+// if the record has been modified since it was read.
+func (s *SpeakerService) Update(ctx context.Context, speaker *types.Speaker) (*types.Speaker, error) {
+	body, err := encode(speaker, SpeakerPath)
+	if err != nil {
+		return nil, err
+	}
+	payload, etag, err := s.client.Update(ctx, SpeakerPath, speaker.ID, speaker.ETag, body)
+	if err != nil {
+		return nil, err
+	}
+	recorded, err := decode[types.Speaker](payload, SpeakerPath)
+	if err != nil {
+		return nil, err
+	}
+	recorded.ETag = etag
+	return recorded, nil
+}
+
+// Delete deletes the speaker record with the given ID.
+func (s *SpeakerService) Delete(ctx context.Context, id string) error {
+	return s.client.Delete(ctx, SpeakerPath, id)
+}
+
+// ProposalService manages the proposal records of the CFP API.
+type ProposalService struct {
+	client *Client
+}
+
+// Proposals returns the service managing the proposal records.
+func (c *Client) Proposals() *ProposalService {
+	return &ProposalService{client: c}
+}
+
+// Get returns the proposal record with the given ID.
+func (s *ProposalService) Get(ctx context.Context, id string) (*types.Proposal, error) {
+	payload, etag, err := s.client.Get(ctx, ProposalPath, id)
+	if err != nil {
+		return nil, err
+	}
+	proposal, err := decode[types.Proposal](payload, ProposalPath)
+	if err != nil {
+		return nil, err
+	}
+	proposal.ETag = etag
+	return proposal, nil
+}
+
+// List returns the page of proposal records selected by opts.
+func (s *ProposalService) List(ctx context.Context, opts ProposalListOptions) (*types.ProposalList, error) {
+	payload, err := s.client.List(ctx, ProposalPath, opts.query())
+	if err != nil {
+		return nil, err
+	}
+	return decode[types.ProposalList](payload, ProposalPath)
+}
+
+// Create creates the proposal record, and returns it as recorded.
+func (s *ProposalService) Create(ctx context.Context, proposal *types.Proposal) (*types.Proposal, error) {
+	body, err := encode(proposal, ProposalPath)
+	if err != nil {
+		return nil, err
+	}
+	payload, etag, err := s.client.Create(ctx, ProposalPath, body)
+	if err != nil {
+		return nil, err
+	}
+	recorded, err := decode[types.Proposal](payload, ProposalPath)
+	if err != nil {
+		return nil, err
+	}
+	recorded.ETag = etag
+	return recorded, nil
+}
+
+// Update replaces the proposal record with the ID of proposal, and returns it
+// as recorded. If the ETag of proposal is set, the update fails with a 412
+// status if the record has been modified since it was read.
+func (s *ProposalService) Update(ctx context.Context, proposal *types.Proposal) (*types.Proposal, error) {
+	body, err := encode(proposal, ProposalPath)
+	if err != nil {
+		return nil, err
+	}
+	payload, etag, err := s.client.Update(ctx, ProposalPath, proposal.ID, proposal.ETag, body)
+	if err != nil {
+		return nil, err
+	}
+	recorded, err := decode[types.Proposal](payload, ProposalPath)
+	if err != nil {
+		return nil, err
+	}
+	recorded.ETag = etag
+	return recorded, nil
+}
+
+// Delete deletes the proposal record with the given ID.
+func (s *ProposalService) Delete(ctx context.Context, id string) error {
+	return s.client.Delete(ctx, ProposalPath, id)
+}
+
+// ReviewService manages the review records of a proposal of the CFP API.
+type ReviewService struct {
+	client *Client
+	path   string
+}
+
+// Reviews returns the service managing the review records of the proposal
+// with the given ID.
+func (c *Client) Reviews(proposalID string) *ReviewService {
+	return &ReviewService{client: c, path: ReviewPath(proposalID)}
+}
+
+// Get returns the review record with the given ID.
+func (s *ReviewService) Get(ctx context.Context, id string) (*types.Review, error) {
+	payload, etag, err := s.client.Get(ctx, s.path, id)
+	if err != nil {
+		return nil, err
+	}
+	review, err := decode[types.Review](payload, s.path)
+	if err != nil {
+		return nil, err
+	}
+	review.ETag = etag
+	return review, nil
+}
+
+// Create creates the review record, and returns it as recorded.
+func (s *ReviewService) Create(ctx context.Context, review *types.Review) (*types.Review, error) {
+	body, err := encode(review, s.path)
+	if err != nil {
+		return nil, err
+	}
+	payload, etag, err := s.client.Create(ctx, s.path, body)
+	if err != nil {
+		return nil, err
+	}
+	recorded, err := decode[types.Review](payload, s.path)
+	if err != nil {
+		return nil, err
+	}
+	recorded.ETag = etag
+	return recorded, nil
+}
+
+// Update replaces the review record with the ID of review, and returns it as
+// recorded. If the ETag of review is set, the update fails with a 412 status
+// if the record has been modified since it was read.
+func (s *ReviewService) Update(ctx context.Context, review *types.Review) (*types.Review, error) {
+	body, err := encode(review, s.path)
+	if err != nil {
+		return nil, err
+	}
+	payload, etag, err := s.client.Update(ctx, s.path, review.ID, review.ETag, body)
+	if err != nil {
+		return nil, err
+	}
+	recorded, err := decode[types.Review](payload, s.path)
+	if err != nil {
+		return nil, err
+	}
+	recorded.ETag = etag
+	return recorded, nil
+}
+
+// Delete deletes the review record with the given ID.
+func (s *ReviewService) Delete(ctx context.Context, id string) error {
+	return s.client.Delete(ctx, s.path, id)
+}
+
+func encode(record interface{}, path string) ([]byte, error) {
+	body, err := json.Marshal(record)
+	if err != nil {
+		return nil, &Error{Reason: ErrCreateRequest, Err: fmt.Errorf("error encoding %s record: %w", path, err)}
+	}
+	return body, nil
+}
+
+func decode[T any](payload []byte, path string) (*T, error) {
+	record := new(T)
+	if err := json.Unmarshal(payload, record); err != nil {
+		return nil, &Error{Reason: ErrUnknown, Err: fmt.Errorf("error decoding %s record: %w", path, err)}
+	}
+	return record, nil
+}
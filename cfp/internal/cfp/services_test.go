@@ -0,0 +1,178 @@
+package cfp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/scottrigby/cfp-api/pkg/types"
+)
+
+func Test_ProposalService(t *testing.T) {
+	g := NewWithT(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			// Records are encoded with the names of the Go fields
+			w.Write([]byte(`{"ID":"ns-talk","Title":"A talk","SpeakerID":"ns-speaker","SpeakerIDs":["ns-speaker"],"Final":true,"Submission":{"Status":"final"}}`))
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			if !bytes.Contains(body, []byte(`"ID":"ns-talk"`)) || !bytes.Contains(body, []byte(`"Submission":{`)) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Write(body)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, server.Client())
+	g.Expect(err).ToNot(HaveOccurred())
+
+	proposal, err := client.Proposals().Get(context.Background(), "ns-talk")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(proposal.ID).To(Equal("ns-talk"))
+	g.Expect(proposal.SpeakerIDs).To(Equal([]string{"ns-speaker"}))
+	g.Expect(proposal.Submission.Status).To(Equal(types.Final))
+
+	proposal.Submission.Status = types.Withdrawn
+	updated, err := client.Proposals().Update(context.Background(), proposal)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(updated).To(Equal(proposal))
+
+	err = client.Proposals().Delete(context.Background(), "ns-talk")
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(IsTerminal(err)).To(BeTrue())
+}
+
+func Test_SpeakerService_IfMatch(t *testing.T) {
+	g := NewWithT(t)
+
+	etag := `"1"`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("ETag", etag)
+			// Lowercase field names are read as well
+			w.Write([]byte(`{"id":"ns-speaker","name":"Speaker"}`))
+		case http.MethodPut:
+			if r.Header.Get("If-Match") != etag {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				return
+			}
+			etag = `"3"`
+			w.Header().Set("ETag", etag)
+			body, _ := io.ReadAll(r.Body)
+			w.Write(body)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, server.Client())
+	g.Expect(err).ToNot(HaveOccurred())
+
+	speaker, err := client.Speakers().Get(context.Background(), "ns-speaker")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(speaker.ETag).To(Equal(`"1"`))
+
+	// The record is modified by another writer after it was read
+	etag = `"2"`
+	speaker.Name = "New name"
+	_, err = client.Speakers().Update(context.Background(), speaker)
+	g.Expect(IsPreconditionFailed(err)).To(BeTrue())
+	g.Expect(IsTerminal(err)).To(BeFalse())
+
+	speaker, err = client.Speakers().Get(context.Background(), "ns-speaker")
+	g.Expect(err).ToNot(HaveOccurred())
+	speaker.Name = "New name"
+	updated, err := client.Speakers().Update(context.Background(), speaker)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(updated.Name).To(Equal("New name"))
+	g.Expect(updated.ETag).To(Equal(`"3"`))
+}
+
+func Test_ProposalService_Problem(t *testing.T) {
+	g := NewWithT(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", types.ProblemContentType)
+		switch r.Method {
+		case http.MethodGet:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"title":"Not Found","status":404,"detail":"could not find proposal with ID 'ns-talk'","code":"NotFound"}`))
+		case http.MethodPost:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"title":"Bad Request","status":400,"detail":"title: title must be specified","code":"ValidationFailed",` +
+				`"errors":[{"field":"title","message":"title must be specified"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, server.Client())
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, err = client.Proposals().Get(context.Background(), "ns-talk")
+	g.Expect(IsNotFound(err)).To(BeTrue())
+	g.Expect(IsValidation(err)).To(BeFalse())
+	g.Expect(err.Error()).To(ContainSubstring("could not find proposal with ID 'ns-talk'"))
+
+	_, err = client.Proposals().Create(context.Background(), &types.Proposal{ID: "ns-talk"})
+	g.Expect(IsValidation(err)).To(BeTrue())
+	g.Expect(IsTerminal(err)).To(BeTrue())
+	var apiErr *Error
+	g.Expect(errors.As(err, &apiErr)).To(BeTrue())
+	g.Expect(apiErr.Reason).To(Equal(ErrCreateProposal))
+	g.Expect(apiErr.FieldErrors).To(Equal([]types.FieldError{{Field: "title", Message: "title must be specified"}}))
+}
+
+func Test_ProposalService_List(t *testing.T) {
+	g := NewWithT(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		g.Expect(r.URL.Path).To(Equal(ProposalPath))
+		query := r.URL.Query()
+		g.Expect(query.Get("speakerID")).To(Equal("ns-speaker"))
+		g.Expect(query.Get("status")).To(Equal(types.Final))
+		g.Expect(query.Get("limit")).To(Equal("1"))
+		g.Expect(query.Has("type")).To(BeFalse())
+
+		if query.Get("continue") == "" {
+			w.Write([]byte(`{"items":[{"id":"ns-a"}],"continue":"bnMtYQ"}`))
+			return
+		}
+		g.Expect(query.Get("continue")).To(Equal("bnMtYQ"))
+		w.Write([]byte(`{"items":[{"id":"ns-b"}]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, server.Client())
+	g.Expect(err).ToNot(HaveOccurred())
+
+	opts := ProposalListOptions{
+		ListOptions: ListOptions{Limit: 1},
+		SpeakerID:   "ns-speaker",
+		Status:      types.Final,
+	}
+	var ids []string
+	for {
+		page, err := client.Proposals().List(context.Background(), opts)
+		g.Expect(err).ToNot(HaveOccurred())
+		for _, p := range page.Items {
+			ids = append(ids, p.ID)
+		}
+		if page.Continue == "" {
+			break
+		}
+		opts.Continue = page.Continue
+	}
+	g.Expect(ids).To(Equal([]string{"ns-a", "ns-b"}))
+}
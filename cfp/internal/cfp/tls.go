@@ -0,0 +1,52 @@
+package cfp
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/scottrigby/cfp-api/pkg/certs"
+)
+
+// TLSOptions configure the TLS connections to the CFP API.
+type TLSOptions struct {
+	// CAFile is the CA bundle verifying the certificate of the CFP API, the
+	// system roots are used if empty.
+	CAFile string
+	// CertFile and KeyFile are the client certificate presented to the CFP
+	// API, reloaded when they change.
+	CertFile string
+	KeyFile  string
+}
+
+// NewHTTPClient returns an HTTP client connecting to the CFP API with the
+// TLS options.
+func NewHTTPClient(opts TLSOptions) (*http.Client, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if opts.CAFile != "" {
+		pem, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in the CA bundle %s", opts.CAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if opts.CertFile != "" || opts.KeyFile != "" {
+		reloader, err := certs.NewReloader(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		config.GetClientCertificate = reloader.GetClientCertificate
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = config
+	return &http.Client{Transport: transport}, nil
+}
@@ -0,0 +1,91 @@
+package cfp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_NewHTTPClient(t *testing.T) {
+	g := NewWithT(t)
+	dir := t.TempDir()
+
+	// A client certificate trusted by the server, and one which is not
+	trustedCert, trustedKey, trusted := newCertificate(g, "trusted")
+	untrustedCert, untrustedKey, _ := newCertificate(g, "untrusted")
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(trusted)
+	server.TLS = &tls.Config{ClientCAs: clientCAs, ClientAuth: tls.RequireAndVerifyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	caFile := filepath.Join(dir, "ca.crt")
+	g.Expect(os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600)).To(Succeed())
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	g.Expect(os.WriteFile(certFile, trustedCert, 0o600)).To(Succeed())
+	g.Expect(os.WriteFile(keyFile, trustedKey, 0o600)).To(Succeed())
+
+	// The server is not trusted without the CA bundle
+	client, err := NewHTTPClient(TLSOptions{})
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = client.Get(server.URL)
+	g.Expect(err).To(HaveOccurred())
+
+	client, err = NewHTTPClient(TLSOptions{CAFile: caFile, CertFile: certFile, KeyFile: keyFile})
+	g.Expect(err).ToNot(HaveOccurred())
+	resp, err := client.Get(server.URL)
+	g.Expect(err).ToNot(HaveOccurred())
+	resp.Body.Close()
+
+	// The client certificate is reloaded when it changes
+	g.Expect(os.WriteFile(certFile, untrustedCert, 0o600)).To(Succeed())
+	g.Expect(os.WriteFile(keyFile, untrustedKey, 0o600)).To(Succeed())
+	later := time.Now().Add(time.Minute)
+	g.Expect(os.Chtimes(certFile, later, later)).To(Succeed())
+	client.CloseIdleConnections()
+	_, err = client.Get(server.URL)
+	g.Expect(err).To(HaveOccurred())
+}
+
+// newCertificate returns the PEM encoded certificate and key of a new self
+// signed client certificate.
+func newCertificate(g *WithT, name string) ([]byte, []byte, *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	g.Expect(err).ToNot(HaveOccurred())
+	cert, err := x509.ParseCertificate(der)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	g.Expect(err).ToNot(HaveOccurred())
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		cert
+}
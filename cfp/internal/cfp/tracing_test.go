@@ -0,0 +1,66 @@
+package cfp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/scottrigby/cfp-api/pkg/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func Test_ClientTracing(t *testing.T) {
+	g := NewWithT(t)
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	var traceparents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents = append(traceparents, r.Header.Get("traceparent"))
+		if r.Method == http.MethodPut {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"id":"ns-speaker"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, server.Client())
+	g.Expect(err).ToNot(HaveOccurred())
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "Reconcile Speaker")
+	_, err = client.Speakers().Get(ctx, "ns-speaker")
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = client.Speakers().Update(ctx, &types.Speaker{ID: "ns/speaker"})
+	g.Expect(err).To(HaveOccurred())
+	parent.End()
+
+	spans := recorder.Ended()
+	g.Expect(spans).To(HaveLen(3))
+
+	get, put := spans[0], spans[1]
+	g.Expect(get.Name()).To(Equal("GET speakers"))
+	g.Expect(get.SpanKind()).To(Equal(trace.SpanKindClient))
+	g.Expect(get.Parent().SpanID()).To(Equal(parent.SpanContext().SpanID()))
+	g.Expect(get.Status().Code).To(Equal(codes.Unset))
+	g.Expect(put.Name()).To(Equal("PUT speakers"))
+	g.Expect(put.SpanContext().TraceID()).To(Equal(parent.SpanContext().TraceID()))
+	g.Expect(put.Status().Code).To(Equal(codes.Error))
+
+	// The trace context of each request is propagated to the API
+	g.Expect(traceparents).To(Equal([]string{
+		"00-" + get.SpanContext().TraceID().String() + "-" + get.SpanContext().SpanID().String() + "-01",
+		"00-" + put.SpanContext().TraceID().String() + "-" + put.SpanContext().SpanID().String() + "-01",
+	}))
+}
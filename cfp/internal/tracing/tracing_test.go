@@ -0,0 +1,48 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func Test_Setup(t *testing.T) {
+	g := NewWithT(t)
+
+	paths := make(chan string, 1)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths <- r.URL.Path
+	}))
+	defer collector.Close()
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	shutdown, err := Setup(context.Background(), "cfp-controller", Options{Exporter: ExporterOTLP, Endpoint: collector.URL})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, span := otel.Tracer("test").Start(context.Background(), "Reconcile Speaker")
+	span.End()
+
+	// The pending spans are flushed to the collector on shutdown
+	g.Expect(shutdown(context.Background())).To(Succeed())
+	g.Expect(paths).To(Receive(Equal("/v1/traces")))
+}
+
+func Test_newExporter(t *testing.T) {
+	g := NewWithT(t)
+
+	exporter, err := newExporter(context.Background(), Options{Exporter: ExporterNone})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(exporter).To(BeNil())
+
+	exporter, err = newExporter(context.Background(), Options{Exporter: ExporterStdout})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(exporter).ToNot(BeNil())
+
+	_, err = newExporter(context.Background(), Options{Exporter: "jaeger"})
+	g.Expect(err).To(MatchError(ContainSubstring("unknown tracing exporter 'jaeger'")))
+}
@@ -17,23 +17,32 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
-	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	talksv1 "github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/api/v1"
+	talksv2 "github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/api/v2"
 	"github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/controllers"
+	"github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/internal/cfp"
+	"github.com/scottrigby/how-to-write-a-reconciler-using-k8s-controller-runtime/cfp/internal/tracing"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -46,6 +55,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(talksv1.AddToScheme(scheme))
+	utilruntime.Must(talksv2.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
 
@@ -55,13 +65,58 @@ func main() {
 		enableLeaderElection bool
 		probeAddr            string
 		cfpAPI               string
+		allowedEndpoints     string
+		minReviews           int
+		defaultInterval      time.Duration
+		eventDedupWindow     time.Duration
+		noCrossNamespaceRefs bool
+		clusterID            string
+		cfpAPIQPS            float64
+		cfpAPIBurst          int
+		endpointLimits       string
+		credentialsSecret    string
+		tlsOptions           cfp.TLSOptions
+		tracingOptions       tracing.Options
+		speakerOptions       controllers.ReconcilerOptions
+		proposalOptions      controllers.ReconcilerOptions
+		reviewOptions        controllers.ReconcilerOptions
 	)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.StringVar(&cfpAPI, "cfp-api-endpoint-address", "http://localhost:50001", "The address of the cfp API.")
+	flag.StringVar(&allowedEndpoints, "cfp-api-allowed-endpoints", "",
+		"The comma-separated addresses of the other cfp APIs that Conferences are allowed to use, none by default.")
+	flag.Float64Var(&cfpAPIQPS, "cfp-api-qps", 20,
+		"The number of requests per second sent to each endpoint of the cfp API, 0 disables the limit.")
+	flag.IntVar(&cfpAPIBurst, "cfp-api-burst", 40, "The number of requests sent at once to each endpoint of the cfp API.")
+	flag.StringVar(&endpointLimits, "cfp-api-endpoint-limits", "",
+		"The comma-separated endpoint=qps:burst limits of the endpoints of the cfp API overriding --cfp-api-qps and --cfp-api-burst.")
+	flag.StringVar(&credentialsSecret, "cfp-api-credentials-secret", "",
+		"The namespace/name of the Secret holding the credentials of the cfp API, either a token or a username and a password.")
+	flag.StringVar(&tlsOptions.CAFile, "cfp-api-ca-file", "",
+		"The CA bundle verifying the certificate of the cfp API, defaults to the system roots.")
+	flag.StringVar(&tlsOptions.CertFile, "cfp-api-client-cert-file", "",
+		"The client certificate presented to the cfp API, reloaded when it changes.")
+	flag.StringVar(&tlsOptions.KeyFile, "cfp-api-client-key-file", "", "The key of the client certificate presented to the cfp API.")
+	flag.StringVar(&tracingOptions.Exporter, "tracing-exporter", tracing.ExporterNone,
+		"The exporter of the spans of the reconciliations and of the cfp API requests: none, stdout or otlp.")
+	flag.StringVar(&tracingOptions.Endpoint, "otlp-endpoint", tracing.DefaultOTLPEndpoint,
+		"The address of the OTLP/HTTP collector the spans are sent to with the otlp exporter.")
+	flag.DurationVar(&defaultInterval, "default-interval", 10*time.Minute,
+		"The interval at which objects without an interval are compared with their record in the cfp API.")
+	flag.DurationVar(&eventDedupWindow, "event-dedup-window", 10*time.Minute,
+		"The duration during which identical events of an object are recorded only once.")
+	flag.IntVar(&minReviews, "min-reviews", 3, "The number of reviews a final proposal needs before a decision can be made on it.")
+	flag.StringVar(&clusterID, "cluster-id", "",
+		"The ID of the cluster in the owner marker of the cfp API records, defaults to the UID of the kube-system namespace.")
+	flag.BoolVar(&noCrossNamespaceRefs, "no-cross-namespace-refs", false,
+		"When set to true, Proposals can only reference Speakers of their own namespace.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	speakerOptions.BindFlags(flag.CommandLine, "speaker")
+	proposalOptions.BindFlags(flag.CommandLine, "proposal")
+	reviewOptions.BindFlags(flag.CommandLine, "review")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -70,7 +125,13 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	shutdownTracing, err := tracing.Setup(context.Background(), "cfp-controller", tracingOptions)
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+
+	mgrOpts := ctrl.Options{
 		Scheme:                 scheme,
 		MetricsBindAddress:     metricsAddr,
 		Port:                   9443,
@@ -88,32 +149,136 @@ func main() {
 		// if you are doing or is intended to do any operation such as perform cleanups
 		// after the manager stops then its usage might be unsafe.
 		// LeaderElectionReleaseOnCancel: true,
-	})
+	}
+
+	// Only the credentials Secret is watched, if any
+	var credentialsRef types.NamespacedName
+	if credentialsSecret != "" {
+		namespace, name, ok := strings.Cut(credentialsSecret, "/")
+		if !ok || namespace == "" || name == "" {
+			setupLog.Error(nil, "invalid credentials Secret, must be namespace/name", "secret", credentialsSecret)
+			os.Exit(1)
+		}
+		credentialsRef = types.NamespacedName{Namespace: namespace, Name: name}
+		mgrOpts.NewCache = cache.BuilderWithOptions(cache.Options{
+			SelectorsByObject: cache.SelectorsByObject{
+				&corev1.Secret{}: {Field: fields.SelectorFromSet(fields.Set{"metadata.namespace": namespace, "metadata.name": name})},
+			},
+		})
+	}
+
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), mgrOpts)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
-	httpClient := http.DefaultClient
+	var allowedCfpAPIs []string
+	for _, endpoint := range strings.Split(allowedEndpoints, ",") {
+		if endpoint = strings.TrimSpace(endpoint); endpoint != "" {
+			allowedCfpAPIs = append(allowedCfpAPIs, endpoint)
+		}
+	}
+
+	httpClient, err := cfp.NewHTTPClient(tlsOptions)
+	if err != nil {
+		setupLog.Error(err, "unable to create the cfp API HTTP client")
+		os.Exit(1)
+	}
+	limits, err := cfp.ParseEndpointLimits(endpointLimits)
+	if err != nil {
+		setupLog.Error(err, "unable to parse the cfp API endpoint limits")
+		os.Exit(1)
+	}
+	rateLimiter, err := cfp.NewRateLimiter(cfp.EndpointLimit{QPS: cfpAPIQPS, Burst: cfpAPIBurst}, limits,
+		append([]string{cfpAPI}, allowedCfpAPIs...)...)
+	if err != nil {
+		setupLog.Error(err, "unable to create the cfp API rate limiter")
+		os.Exit(1)
+	}
+
+	var credentials *cfp.CredentialStore
+	if credentialsSecret != "" {
+		credentials = &cfp.CredentialStore{Endpoint: cfpAPI}
+		if err = (&controllers.CredentialsReconciler{
+			Client:        mgr.GetClient(),
+			EventRecorder: controllers.NewDedupEventRecorder(mgr.GetEventRecorderFor("credentials-controller"), eventDedupWindow),
+			Store:         credentials,
+			SecretRef:     credentialsRef,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Credentials")
+			os.Exit(1)
+		}
+	}
+
+	if clusterID == "" {
+		clusterID, err = controllers.ClusterID(context.Background(), mgr.GetAPIReader())
+		if err != nil {
+			setupLog.Error(err, "unable to get the cluster ID")
+			os.Exit(1)
+		}
+	}
 
 	if err = (&controllers.SpeakerReconciler{
-		Client:         mgr.GetClient(),
-		HTTPClient:     httpClient,
-		ControllerName: "speaker-controller",
-		CfpAPI:         cfpAPI,
-	}).SetupWithManager(mgr); err != nil {
+		Client:           mgr.GetClient(),
+		HTTPClient:       httpClient,
+		EventRecorder:    controllers.NewDedupEventRecorder(mgr.GetEventRecorderFor("speaker-controller"), eventDedupWindow),
+		ControllerName:   "speaker-controller",
+		CfpAPI:           cfpAPI,
+		AllowedEndpoints: allowedCfpAPIs,
+		RateLimiter:      rateLimiter,
+		Credentials:      credentials,
+		DefaultInterval:  defaultInterval,
+		ClusterID:        clusterID,
+	}).SetupWithManagerAndOptions(mgr, speakerOptions); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Speaker")
 		os.Exit(1)
 	}
 	if err = (&controllers.ProposalReconciler{
-		Client:         mgr.GetClient(),
-		HTTPClient:     httpClient,
-		ControllerName: "propsal-controller",
-		CfpAPI:         cfpAPI,
-	}).SetupWithManager(mgr); err != nil {
+		Client:               mgr.GetClient(),
+		HTTPClient:           httpClient,
+		EventRecorder:        controllers.NewDedupEventRecorder(mgr.GetEventRecorderFor("propsal-controller"), eventDedupWindow),
+		ControllerName:       "propsal-controller",
+		CfpAPI:               cfpAPI,
+		AllowedEndpoints:     allowedCfpAPIs,
+		RateLimiter:          rateLimiter,
+		Credentials:          credentials,
+		DefaultInterval:      defaultInterval,
+		MinReviews:           minReviews,
+		ClusterID:            clusterID,
+		NoCrossNamespaceRefs: noCrossNamespaceRefs,
+	}).SetupWithManagerAndOptions(mgr, proposalOptions); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Proposal")
 		os.Exit(1)
 	}
+	if err = (&controllers.ReviewReconciler{
+		Client:           mgr.GetClient(),
+		HTTPClient:       httpClient,
+		EventRecorder:    controllers.NewDedupEventRecorder(mgr.GetEventRecorderFor("review-controller"), eventDedupWindow),
+		ControllerName:   "review-controller",
+		CfpAPI:           cfpAPI,
+		AllowedEndpoints: allowedCfpAPIs,
+		RateLimiter:      rateLimiter,
+		Credentials:      credentials,
+	}).SetupWithManagerAndOptions(mgr, reviewOptions); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Review")
+		os.Exit(1)
+	}
+	// Webhooks can be disabled when running the manager locally, e.g. with `make run`
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = (&talksv2.Proposal{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Proposal")
+			os.Exit(1)
+		}
+		if err = (&talksv1.Proposal{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Proposal")
+			os.Exit(1)
+		}
+		if err = (&talksv1.Speaker{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Speaker")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -130,4 +295,11 @@ func main() {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
+
+	// Flush the spans of the last reconciliations
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(ctx); err != nil {
+		setupLog.Error(err, "unable to flush the spans")
+	}
 }